@@ -0,0 +1,75 @@
+package grffr
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.cph.dev/grffr/logging"
+)
+
+// accessLogLevel is the default level to log a request with the given status at.
+func accessLogLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// logRequests is a middleware logging every request once it has been served.
+//
+// Request attributes are added to the request context with [logging.AppendCtx],
+// so logs from handlers carry the same request fields.
+func (a *App) logRequests(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		cfg := a.configuration.AccessLog
+		if cfg.Disabled ||
+			slices.Contains(cfg.ExcludedPaths, r.URL.Path) ||
+			(!cfg.IncludeHealth && a.isHealthPath(r.URL.Path)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		ctx := logging.AppendCtx(r.Context(),
+			slog.Group("request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			),
+		)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		sampled := cfg.SampleRate <= 0 || rand.Float64() < cfg.SampleRate
+		if status < http.StatusInternalServerError && !sampled {
+			return
+		}
+
+		level := accessLogLevel(status)
+		if cfg.Level != nil {
+			level = cfg.Level(status)
+		}
+		a.logger.LogAttrs(ctx, level, "HTTP request",
+			slog.String("route", routePattern(r)),
+			slog.Group("response",
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+			),
+		)
+	}
+	return http.HandlerFunc(fn)
+}
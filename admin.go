@@ -0,0 +1,62 @@
+package grffr
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminRouter returns the router of the admin HTTP server.
+//
+// Management end-points registered here are only served when the admin
+// server is enabled with [options.WithAdminAddr].
+//
+// The admin server provides:
+//   - GET /config returns the effective configuration, annotated with
+//     the source of each value and with secrets redacted.
+//   - POST /config/reload reloads configuration, see [Reloadable].
+//   - GET /admin/log/level returns the log level of the default logger.
+//   - PUT /admin/log/level changes the log level, e.g. {"level": "debug",
+//     "revert_after": "10m"} logs debug for ten minutes. The level of a
+//     logger given with [options.WithLogger] cannot be changed, which is
+//     answered with 409 Conflict.
+func (a *App) AdminRouter() chi.Router {
+	return a.adminRouter
+}
+
+// adminEnabled reports whether the admin HTTP server is enabled.
+func (a *App) adminEnabled() bool {
+	return a.configuration.AdminAddr != ""
+}
+
+func (a *App) initAdminServer() error {
+	if !a.adminEnabled() {
+		return nil
+	}
+
+	addr := a.configuration.AdminAddr
+	err := addrAvailable(addr)
+	if err != nil {
+		return fmt.Errorf("admin address %s unavailable: %w", addr, err)
+	}
+
+	a.adminRouter.Handle("GET /config", a.configHandler())
+	a.adminRouter.Handle("POST /config/reload", a.reloadHandler())
+	a.adminRouter.Handle("GET /admin/log/level", a.logLevelHandler())
+	a.adminRouter.Handle("PUT /admin/log/level", a.setLogLevelHandler())
+
+	timeouts := a.configuration.HTTPTimeouts
+	a.adminServer = &http.Server{
+		Addr:    addr,
+		Handler: a.adminRouter,
+
+		ReadTimeout:       timeouts.Read,
+		ReadHeaderTimeout: timeouts.ReadHeader,
+		WriteTimeout:      timeouts.Write,
+		IdleTimeout:       timeouts.Idle,
+		MaxHeaderBytes:    a.configuration.HTTPMaxHeaderBytes,
+	}
+
+	return nil
+}
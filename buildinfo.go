@@ -0,0 +1,94 @@
+package grffr
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"path"
+	"runtime/debug"
+	"time"
+)
+
+// BuildInfo identifies the application and the build it is running.
+type BuildInfo struct {
+	Name      string    `json:"name,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Revision  string    `json:"revision,omitempty"`
+	Modified  bool      `json:"modified,omitempty"`
+	Time      time.Time `json:"time,omitzero"`
+	GoVersion string    `json:"go_version,omitempty"`
+	Grffr     string    `json:"grffr"`
+}
+
+// readBuildInfo returns the build information embedded in the binary.
+//
+// Name and version, if given, take precedence over the module path
+// and version of the main module.
+func readBuildInfo(name, version string) BuildInfo {
+	info := BuildInfo{
+		Name:    name,
+		Version: version,
+		Grffr:   grffrVersion,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.GoVersion = bi.GoVersion
+	if info.Name == "" && bi.Main.Path != "" {
+		info.Name = path.Base(bi.Main.Path)
+	}
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		case "vcs.time":
+			info.Time, _ = time.Parse(time.RFC3339, setting.Value)
+		}
+	}
+
+	return info
+}
+
+// ReleaseID returns the revision, marked if modified, or the version
+// if the revision is unknown.
+func (b BuildInfo) ReleaseID() string {
+	if b.Revision == "" {
+		return b.Version
+	}
+	if b.Modified {
+		return b.Revision + "-dirty"
+	}
+
+	return b.Revision
+}
+
+// logAttrs returns the build information as log attributes.
+func (b BuildInfo) logAttrs() []slog.Attr {
+	return []slog.Attr{
+		slog.String("name", b.Name),
+		slog.String("version", b.Version),
+		slog.String("release", b.ReleaseID()),
+	}
+}
+
+// BuildInfo returns the identity and build information of the application.
+func (a *App) BuildInfo() BuildInfo {
+	return a.buildInfo
+}
+
+func (a *App) defaultVersionHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(a.buildInfo)
+	}
+	return http.HandlerFunc(fn)
+}
@@ -7,9 +7,11 @@ import (
 	"slices"
 	"sync"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/hashicorp/go-multierror"
 	"go.cph.dev/grffr/data"
 	"go.cph.dev/grffr/logging"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -17,10 +19,14 @@ import (
 //
 // The app will start up the component and make sure it is
 // also stopped again during the shutdown sequence after
-// all incoming requests are drained.cccccbrfgkcdduculkblceldlhevurnlcgbtgiffgvtj
+// all incoming requests are drained.
 //
 // Implement [Name() string] (NamedComponent interface)
 // for more context during logging, etc.
+//
+// Implement [DependsOn() []string] (Dependent interface) to have
+// the component initialised and started after the named components
+// it depends on, and stopped before them.
 func (a *App) AddComponent(c Component) {
 	a.components = append(a.components, c)
 }
@@ -33,6 +39,9 @@ func (a *App) AddComponent(c Component) {
 //   - Stop() stops the component.
 //   - Provided context.Context should be checked
 //     for Done state and exit early if needed.
+//
+// Components are initialised and started in dependency order
+// (see [Dependent]) and stopped in the reverse order.
 type Component interface {
 	Init(ctx context.Context) error
 	Start(ctx context.Context) error
@@ -52,6 +61,19 @@ type Named interface {
 	Name() string
 }
 
+// ReadyNotifier is a component that signals when it is ready.
+//
+// Start may block for as long as the component runs, in which case the
+// channel returned by Ready must be closed once the component is ready
+// to serve. Dependent components are not started, and the web server
+// does not accept traffic, until all ReadyNotifier components are ready.
+//
+// Components not implementing ReadyNotifier are considered ready as soon
+// as Start has been called.
+type ReadyNotifier interface {
+	Ready() <-chan struct{}
+}
+
 // WantLogger is a component with a logger.
 //
 // UseLogger will be called during initialization.
@@ -61,11 +83,20 @@ type WantLogger interface {
 
 // WantTracer is a component with a tracer.
 //
-// UseTracer will be called during initialization.
+// UseTracer will be called during initialization with a tracer
+// named after the component.
 type WantTracer interface {
 	UseTracer(trace.Tracer)
 }
 
+// WantMeter is a component recording metrics.
+//
+// UseMeter will be called during initialization with a meter
+// named after the component.
+type WantMeter interface {
+	UseMeter(metric.Meter)
+}
+
 // WantSQL is a component with a SQL connection.
 //
 // UseSQL will be called during initialization.
@@ -73,6 +104,14 @@ type WantSQL interface {
 	UseSQL(data.SQL)
 }
 
+// WantRouter is a component registering its own HTTP end-points.
+//
+// UseRouter will be called during initialization with the
+// application's router.
+type WantRouter interface {
+	UseRouter(chi.Router)
+}
+
 func (a *App) initComponents(ctx context.Context) error {
 	var result error
 	for c := range slices.Values(a.components) {
@@ -80,11 +119,17 @@ func (a *App) initComponents(ctx context.Context) error {
 			logger.UseLogger(a.logger)
 		}
 		if tracer, ok := c.(WantTracer); ok {
-			tracer.UseTracer(a.tracer)
+			tracer.UseTracer(a.tracerProvider.Tracer(componentName(c)))
+		}
+		if meter, ok := c.(WantMeter); ok {
+			meter.UseMeter(a.meterProvider.Meter(componentName(c)))
 		}
 		if sql, ok := c.(WantSQL); ok {
 			sql.UseSQL(a.sql)
 		}
+		if router, ok := c.(WantRouter); ok {
+			router.UseRouter(a.router)
+		}
 		if err := c.Init(ctx); err != nil {
 			result = multierror.Append(result, err)
 		}
@@ -93,62 +138,178 @@ func (a *App) initComponents(ctx context.Context) error {
 	return result
 }
 
+// startComponents in dependency order.
+//
+// Each component is started once the components it depends on are ready,
+// and startComponents returns when all components are ready, a component
+// fails to start or the startup timeout is exceeded.
 func (a *App) startComponents(
 	ctx context.Context,
 	exit *sync.WaitGroup,
-) {
-	for c := range slices.Values(a.components) {
-		startCtx := ctx
+	escalate func(error),
+) error {
+	readyCtx, cancel := context.WithCancel(ctx)
+	if a.configuration.StartupTimeout > 0 {
+		readyCtx, cancel = context.WithTimeout(ctx, a.configuration.StartupTimeout)
+	}
+	defer cancel()
+
+	byName := map[string]int{}
+	supervisors := make([]*supervisor, 0, len(a.components))
+	for i, c := range a.components {
 		if named, ok := c.(Named); ok {
-			startCtx = logging.AppendCtx(startCtx,
-				slog.Group("component",
-					slog.String("named", named.Name()),
-					slog.String("type", fmt.Sprintf("%T", c)),
-				),
-			)
-		} else {
-			startCtx = logging.AppendCtx(startCtx,
-				slog.Group("component",
-					slog.String("type", fmt.Sprintf("%T", c)),
-				),
-			)
+			byName[named.Name()] = i
+		}
+		s := newSupervisor(c, a.configuration.Supervision)
+		s.key = a.componentKeys[i]
+		s.onPanic = func(ctx context.Context) {
+			a.recordPanic(ctx, "component")
+		}
+		supervisors = append(supervisors, s)
+	}
+	a.supervisorsMu.Lock()
+	a.supervisors = supervisors
+	a.supervisorsMu.Unlock()
+
+	runCtx := context.WithoutCancel(ctx)
+	for i, c := range a.components {
+		if dependent, ok := c.(Dependent); ok {
+			for dep := range slices.Values(dependent.DependsOn()) {
+				if err := waitReady(readyCtx, supervisors[byName[dep]]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("component %s not started: %w", componentName(c), context.Cause(ctx))
 		}
+
+		startCtx := componentContext(runCtx, c)
 		slog.InfoContext(startCtx, "Starting")
 
+		supervisors[i].started.Store(true)
 		exit.Add(1)
 		go func() {
 			defer exit.Done()
 
-			if err := c.Start(startCtx); err != nil {
-				slog.WarnContext(startCtx, "Starting component failed", logging.Error(err))
-			}
+			supervisors[i].run(ctx, startCtx, escalate)
 		}()
 	}
+
+	for s := range slices.Values(supervisors) {
+		if err := waitReady(readyCtx, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// waitReady blocks until the supervised component is ready, fails to
+// start or ctx is done.
+func waitReady(ctx context.Context, s *supervisor) error {
+	c := s.component
+	notifier, ok := c.(ReadyNotifier)
+	if !ok {
+		return nil
+	}
+
+	done := s.done
+	for {
+		select {
+		case <-notifier.Ready():
+			return nil
+		case <-done:
+			if err := s.failure(); err != nil {
+				return fmt.Errorf("starting component %s: %w", componentName(c), err)
+			}
+			// Start returned without error, readiness may still follow
+			done = nil
+		case <-ctx.Done():
+			err := fmt.Errorf("waiting for component %s to become ready: %w", componentName(c), context.Cause(ctx))
+			if lastErr := s.Status().LastError; lastErr != "" {
+				err = fmt.Errorf("%w, last error: %s", err, lastErr)
+			}
+			return err
+		}
+	}
+}
+
+// stopComponents in the reverse order of which they were started.
+//
+// Components never started, e.g. after a startup failure, are not stopped.
 func (a *App) stopComponents(ctx context.Context) error {
 	var result error
 	a.logger.Info("Stopping components.")
-	for c := range slices.Values(a.components) {
-		if named, ok := c.(Named); ok {
-			ctx = logging.AppendCtx(ctx,
-				slog.Group("component",
-					slog.String("named", named.Name()),
-					slog.String("type", fmt.Sprintf("%T", c)),
-				),
-			)
-		} else {
-			ctx = logging.AppendCtx(ctx,
-				slog.Group("component",
-					slog.String("type", fmt.Sprintf("%T", c)),
-				),
-			)
+	a.supervisorsMu.Lock()
+	supervisors := a.supervisors
+	a.supervisorsMu.Unlock()
+	for i, c := range slices.Backward(a.components) {
+		if i >= len(supervisors) || !supervisors[i].started.Load() {
+			continue
 		}
-		a.logger.InfoContext(ctx, "Stopping component")
-		if err := c.Stop(ctx); err != nil {
+		stopCtx := componentContext(ctx, c)
+		a.logger.InfoContext(stopCtx, "Stopping component")
+		if err := c.Stop(stopCtx); err != nil {
 			result = multierror.Append(result, err)
 		}
+		supervisors[i].setState(ComponentStateStopped, nil)
 	}
 
 	return result
 }
+
+// componentContext returns ctx with the component added to the log fields.
+func componentContext(ctx context.Context, c Component) context.Context {
+	if named, ok := c.(Named); ok {
+		return logging.AppendCtx(ctx,
+			slog.Group("component",
+				slog.String("named", named.Name()),
+				slog.String("type", fmt.Sprintf("%T", c)),
+			),
+		)
+	}
+
+	return logging.AppendCtx(ctx,
+		slog.Group("component",
+			slog.String("type", fmt.Sprintf("%T", c)),
+		),
+	)
+}
+
+// componentName returns the name of a [Named] component, or the
+// type of the component if it is not named.
+func componentName(c Component) string {
+	if named, ok := c.(Named); ok {
+		return named.Name()
+	}
+
+	return fmt.Sprintf("%T", c)
+}
+
+// componentKeys returns a unique key for each of components, used to
+// report their status, health and metrics.
+//
+// Components are keyed by name. Unnamed components sharing a type are
+// keyed by type and their position among those components, e.g.
+// "*pkg.Worker#2".
+func componentKeys(components []Component) []string {
+	counts := map[string]int{}
+	for c := range slices.Values(components) {
+		counts[componentName(c)]++
+	}
+
+	keys := make([]string, 0, len(components))
+	seen := map[string]int{}
+	for c := range slices.Values(components) {
+		key := componentName(c)
+		if _, named := c.(Named); !named && counts[key] > 1 {
+			seen[key]++
+			key = fmt.Sprintf("%s#%d", key, seen[key])
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
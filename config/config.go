@@ -0,0 +1,390 @@
+// Package config populates configuration structs from files and
+// environment variables.
+//
+// Fields are populated from environment variables named by struct tags:
+//
+//	type Config struct {
+//		DatabaseURL *url.URL          `env:"DB_URL" required:"true"`
+//		Timeout     time.Duration     `env:"TIMEOUT" default:"5s"`
+//		Origins     []string          `env:"ORIGINS"`
+//		Labels      map[string]string `env:"LABELS"`
+//		Cache       CacheConfig       `prefix:"CACHE_"`
+//	}
+//
+// Tags:
+//   - env names the environment variable of the field.
+//   - default is used when the field is not set otherwise.
+//   - required reports an error if the field is not set by any source.
+//   - prefix is prepended to environment variable names of nested struct
+//     fields. Nested structs without a prefix are populated as well.
+//   - key names the field in configuration files, see below.
+//   - secret names a file in the secrets directory, see below.
+//
+// Empty environment variables are considered not set.
+//
+// Supported field types are strings, booleans, integers, floats,
+// [time.Duration], [url.URL], types implementing [encoding.TextUnmarshaler],
+// slices of these (comma separated), maps of these with string keys
+// (comma separated key=value pairs) and pointers to these. Pointer fields
+// are left nil if not set.
+//
+// # Files
+//
+// Configuration files in YAML (.yaml, .yml), TOML (.toml) or JSON (.json)
+// are loaded with WithFiles. Keys are matched to fields by the key tag or,
+// without it, to the field name ignoring case, underscores and dashes,
+// so database_url matches DatabaseURL. Nested structs are tables/objects.
+// Keys not matching any field are reported as errors.
+//
+// # Secrets
+//
+// Values can be read from files, typically holding a [Secret]:
+//
+//	type Config struct {
+//		Password Secret `env:"DB_PASSWORD" secret:"db_password"`
+//	}
+//
+// If the environment variable with the _FILE suffix is set, DB_PASSWORD_FILE
+// above, the value is read from the file it names. Setting both
+// DB_PASSWORD and DB_PASSWORD_FILE is an error.
+//
+// If the field has a secret tag, the value is read from the file of that
+// name in the secrets directory, /run/secrets by default, if it exists.
+//
+// A single trailing newline is removed from values read from files.
+// Files are read on every call to Load, so secrets are re-read when
+// configuration is reloaded.
+//
+// # Validation
+//
+// If the struct implements [Validator], Validate is called once all
+// values are loaded and valid, to check values against each other.
+//
+// # Precedence
+//
+// Values are applied in the following order, where later sources override
+// earlier ones:
+//
+//  1. default tags
+//  2. files, in the order given
+//  3. files in the secrets directory
+//  4. environment variables, or files named by _FILE variables
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// fileSuffix is appended to environment variable names to read the value
+// from the file named by the variable.
+const fileSuffix = "_FILE"
+
+// ErrRequired is reported for required fields not set.
+var ErrRequired = errors.New("required value not set")
+
+// FieldError is a missing or invalid configuration value.
+type FieldError struct {
+	// Field is the path of the struct field, e.g. Cache.TTL.
+	Field string
+
+	// Key is the name of the environment variable, or the file and key
+	// the value was read from.
+	Key string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Key, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validator is a configuration struct validating its values.
+type Validator interface {
+	Validate() error
+}
+
+// Option changes how configuration is loaded.
+type Option func(*loader)
+
+// WithPrefix prepends prefix to the names of all environment variables.
+func WithPrefix(prefix string) Option {
+	return func(l *loader) {
+		l.prefix = prefix
+	}
+}
+
+// WithLookup sets the function used to look up environment variables.
+//
+// Default is [os.LookupEnv].
+func WithLookup(lookup func(key string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookup = lookup
+	}
+}
+
+// WithFiles loads configuration files, in the order given, before
+// environment variables are applied.
+//
+// Missing files are reported as errors.
+func WithFiles(paths ...string) Option {
+	return func(l *loader) {
+		for _, path := range paths {
+			l.files = append(l.files, file{path: path})
+		}
+	}
+}
+
+// WithOptionalFiles loads configuration files like WithFiles, but
+// skips files that do not exist, e.g. environment specific overlays.
+func WithOptionalFiles(paths ...string) Option {
+	return func(l *loader) {
+		for _, path := range paths {
+			l.files = append(l.files, file{path: path, optional: true})
+		}
+	}
+}
+
+// WithSecretsDir sets the directory of files read for fields with
+// a secret tag.
+//
+// Default is /run/secrets.
+func WithSecretsDir(dir string) Option {
+	return func(l *loader) {
+		l.secretsDir = dir
+	}
+}
+
+// WithSources records the source of each value set in sources, keyed
+// by field path.
+func WithSources(sources Sources) Option {
+	return func(l *loader) {
+		l.report = sources
+	}
+}
+
+type file struct {
+	path     string
+	optional bool
+}
+
+type loader struct {
+	prefix string
+	lookup func(key string) (string, bool)
+	files  []file
+
+	secretsDir string
+
+	// sources holds the sources of fields set, by path.
+	sources Sources
+
+	// report receives the sources once loaded, if set.
+	report Sources
+}
+
+// field is a configuration value of a struct.
+type field struct {
+	path     string
+	env      string
+	def      *string
+	secret   string
+	required bool
+	value    reflect.Value
+}
+
+// Load populates the struct pointed to by dst from files and
+// environment variables.
+//
+// All missing and invalid values are reported in the returned error,
+// each as a [*FieldError] where the value is at fault.
+func Load(dst any, opts ...Option) error {
+	l := loader{
+		lookup:     os.LookupEnv,
+		secretsDir: "/run/secrets",
+		sources:    Sources{},
+	}
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: destination must be a non-nil pointer to a struct, got %T", dst)
+	}
+	fields := l.fields(v.Elem(), "", l.prefix)
+
+	var errs error
+	for _, f := range fields {
+		if f.def == nil {
+			continue
+		}
+		if err := setString(f.value, *f.def); err != nil {
+			errs = errors.Join(errs, &FieldError{Field: f.path, Key: "default", Err: err})
+			continue
+		}
+		l.sources[f.path] = Source{Kind: SourceDefault}
+	}
+
+	for _, file := range l.files {
+		errs = errors.Join(errs, l.loadFile(v.Elem(), file))
+	}
+
+	for _, f := range fields {
+		if f.secret == "" {
+			continue
+		}
+		path := filepath.Join(l.secretsDir, f.secret)
+		raw, err := readFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			err = setString(f.value, raw)
+		}
+		if err != nil {
+			errs = errors.Join(errs, &FieldError{Field: f.path, Key: path, Err: err})
+			continue
+		}
+		l.sources[f.path] = Source{Kind: SourceSecret, Key: path}
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		key, raw, ok, err := l.lookupEnv(f.env)
+		if err == nil && !ok {
+			continue
+		}
+		if err == nil {
+			err = setString(f.value, raw)
+		}
+		if err != nil {
+			errs = errors.Join(errs, &FieldError{Field: f.path, Key: key, Err: err})
+			continue
+		}
+		l.sources[f.path] = Source{Kind: SourceEnv, Key: key}
+	}
+
+	if l.report != nil {
+		maps.Copy(l.report, l.sources)
+	}
+
+	for _, f := range fields {
+		if _, ok := l.sources[f.path]; f.required && !ok {
+			key := f.env
+			if key == "" {
+				key = f.path
+			}
+			errs = errors.Join(errs, &FieldError{Field: f.path, Key: key, Err: ErrRequired})
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+
+	if v, ok := dst.(Validator); ok {
+		return v.Validate()
+	}
+
+	return nil
+}
+
+// fields returns the configuration values of the struct v, descending
+// into nested structs.
+func (l *loader) fields(v reflect.Value, path, prefix string) []field {
+	var fields []field
+
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+
+		name, hasEnv := f.Tag.Lookup("env")
+		if !hasEnv && isNested(f.Type) {
+			fields = append(fields, l.fields(nested(fv), fieldPath, prefix+f.Tag.Get("prefix"))...)
+			continue
+		}
+
+		field := field{
+			path:     fieldPath,
+			required: f.Tag.Get("required") == "true",
+			value:    fv,
+		}
+		if hasEnv {
+			field.env = prefix + name
+		}
+		if def, ok := f.Tag.Lookup("default"); ok {
+			field.def = &def
+		}
+		field.secret = f.Tag.Get("secret")
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// lookupEnv looks up the environment variable name, or reads the file
+// named by name with the _FILE suffix. It returns the variable the value
+// was read from.
+func (l *loader) lookupEnv(name string) (key, value string, ok bool, err error) {
+	value, ok = l.lookup(name)
+	ok = ok && value != ""
+
+	path, hasFile := l.lookup(name + fileSuffix)
+	if !hasFile || path == "" {
+		return name, value, ok, nil
+	}
+	key = name + fileSuffix
+	if ok {
+		return key, "", false, fmt.Errorf("%s and %s are both set", name, key)
+	}
+
+	value, err = readFile(path)
+	if err != nil {
+		return key, "", false, err
+	}
+
+	return key, value, true, nil
+}
+
+// readFile returns the contents of the file at path without a trailing
+// newline.
+func readFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimSuffix(string(b), "\n")
+
+	return strings.TrimSuffix(s, "\r"), nil
+}
+
+// nested returns the struct held by v, allocating it if v is a nil pointer.
+func nested(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Pointer {
+		return v
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+
+	return v.Elem()
+}
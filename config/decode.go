@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	urlType             = reflect.TypeFor[url.URL]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isNested reports whether t is a struct, or pointer to a struct,
+// holding configuration fields rather than being a value itself.
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == urlType {
+		return false
+	}
+
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// setString parses s into v according to the type of v.
+//
+// Errors never include s, which may be a secret.
+func setString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setString(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			// Errors of unmarshalers commonly quote the value
+			return fmt.Errorf("invalid %s", v.Type())
+		}
+		return nil
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("invalid duration")
+		}
+		v.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return errors.New("invalid URL")
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("invalid boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return errors.New("invalid integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return errors.New("invalid unsigned integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return errors.New("invalid number")
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := slices.Collect(splitList(s))
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setString(slice.Index(i), part); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		m := reflect.MakeMap(v.Type())
+		i := 0
+		for part := range splitList(s) {
+			key, value, ok := strings.Cut(part, "=")
+			if !ok {
+				return fmt.Errorf("item %d: invalid key=value pair", i)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setString(elem, strings.TrimSpace(value)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+			i++
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// splitList iterates the items of a comma separated list, trimming
+// spaces and skipping empty items.
+func splitList(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for item := range strings.SplitSeq(s, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Kinds of sources of configuration values.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceSecret  = "secret"
+	SourceEnv     = "env"
+
+	// SourceOption is a value set in code rather than loaded.
+	SourceOption = "option"
+)
+
+// Source is where a configuration value was set from.
+type Source struct {
+	// Kind of source, e.g. [SourceEnv], or empty if the value is not set.
+	Kind string `json:"source,omitempty"`
+
+	// Key is the environment variable, file and key, or secret file the
+	// value was read from, if any.
+	Key string `json:"key,omitempty"`
+}
+
+// Sources are the sources of configuration values, keyed by field path,
+// e.g. Cache.TTL.
+type Sources map[string]Source
+
+// Value is a configuration value annotated with its source.
+type Value struct {
+	Value any `json:"value"`
+	Source
+}
+
+// maxDepth limits how deep Describe descends into nested structs and
+// collections.
+const maxDepth = 8
+
+// Describe returns the values of the struct pointed to by v, keyed by
+// field path, annotated with their sources.
+//
+// Only configuration values are described: nested structs are descended
+// into, while pointers to structs of other packages, interfaces and
+// functions are given by their type only, so values such as clients and
+// handlers are neither walked nor exposed. Fields without a source of
+// their own take the source of the struct holding them. Values are
+// converted for display, with [Secret] values, fields with a secret tag,
+// values read from files and passwords of URLs redacted, and durations
+// formatted.
+func Describe(v any, sources Sources) map[string]Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return map[string]Value{}
+	}
+
+	d := describer{
+		values:  map[string]Value{},
+		sources: sources,
+		pkg:     rv.Type().PkgPath(),
+		visited: map[uintptr]bool{},
+	}
+	d.describe(rv, "", Source{}, 0)
+
+	return d.values
+}
+
+type describer struct {
+	values  map[string]Value
+	sources Sources
+
+	// pkg is the package of the described struct. Nested structs held
+	// by pointer are only descended into if declared in the same package.
+	pkg string
+
+	// visited holds the nested structs being described, to break cycles.
+	visited map[uintptr]bool
+}
+
+func (d *describer) describe(v reflect.Value, path string, parent Source, depth int) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+		source, ok := d.sources[fieldPath]
+		if !ok {
+			source = parent
+		}
+
+		fv := v.Field(i)
+		if !d.isNested(f) {
+			value := display(fv, 0)
+			if isSecret(f, source) && !fv.IsZero() {
+				value = redacted
+			}
+			d.values[fieldPath] = Value{Value: value, Source: source}
+			continue
+		}
+		if depth >= maxDepth {
+			d.values[fieldPath] = Value{Value: f.Type.String(), Source: source}
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				d.values[fieldPath] = Value{Source: source}
+				continue
+			}
+			if d.visited[fv.Pointer()] {
+				d.values[fieldPath] = Value{Value: f.Type.String(), Source: source}
+				continue
+			}
+			d.visited[fv.Pointer()] = true
+			d.describe(fv.Elem(), fieldPath, source, depth+1)
+			delete(d.visited, fv.Pointer())
+			continue
+		}
+		d.describe(fv, fieldPath, source, depth+1)
+	}
+}
+
+// isNested reports whether the field f holds nested configuration to
+// descend into.
+func (d *describer) isNested(f reflect.StructField) bool {
+	if _, hasEnv := f.Tag.Lookup("env"); hasEnv || !isNested(f.Type) || !hasExportedFields(f.Type) {
+		return false
+	}
+
+	return f.Type.Kind() != reflect.Pointer || f.Type.Elem().PkgPath() == d.pkg
+}
+
+// isSecret reports whether the field f holds a secret, whatever its type,
+// because it has a secret tag or its value was read from a secret file or
+// a file named by a _FILE environment variable.
+func isSecret(f reflect.StructField, source Source) bool {
+	_, tagged := f.Tag.Lookup("secret")
+
+	return tagged || source.Kind == SourceSecret || strings.HasSuffix(source.Key, fileSuffix)
+}
+
+// hasExportedFields reports whether the struct, or pointer to struct, t
+// has any exported fields.
+func hasExportedFields(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	secretType        = reflect.TypeFor[Secret]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// display converts v to a value suitable for display.
+//
+// Only the kinds of values configuration is loaded into are displayed,
+// all other values are given by their type.
+func display(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Func, reflect.Map, reflect.Slice, reflect.Chan:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if depth >= maxDepth {
+		return v.Type().String()
+	}
+
+	switch v.Type() {
+	case secretType:
+		return v.Interface().(Secret).String()
+	case urlType:
+		u := v.Interface().(url.URL)
+		return u.Redacted()
+	case durationType:
+		return time.Duration(v.Int()).String()
+	}
+	if v.Type().Implements(textMarshalerType) && v.Kind() != reflect.Pointer {
+		if b, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(b)
+		}
+		return v.Type().String()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		switch elem := v.Type().Elem(); {
+		case elem.Kind() == reflect.Pointer, elem.Kind() == reflect.Interface:
+			return v.Type().String()
+		case elem.Kind() == reflect.Struct && elem != urlType && elem != secretType && !elem.Implements(textMarshalerType):
+			return v.Type().String()
+		}
+		return display(v.Elem(), depth+1)
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range v.Len() {
+			items[i] = display(v.Index(i), depth+1)
+		}
+		return items
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			m[fmt.Sprint(display(iter.Key(), depth+1))] = display(iter.Value(), depth+1)
+		}
+		return m
+	case reflect.Interface:
+		return v.Elem().Type().String()
+	default:
+		return v.Type().String()
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile applies the values of a configuration file to the struct v.
+func (l *loader) loadFile(v reflect.Value, f file) error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if f.optional && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(f.path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	case ".json":
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		err = d.Decode(&values)
+	default:
+		return fmt.Errorf("%s: unsupported file type %q", f.path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+
+	return l.applyMap(v, values, f.path, "", "")
+}
+
+// applyMap applies values to the fields of the struct v.
+//
+// keyPath is the dotted path of values in the file, and path the
+// path of v in the configuration struct.
+func (l *loader) applyMap(v reflect.Value, values map[string]any, file, keyPath, path string) error {
+	var errs error
+
+	t := v.Type()
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value := values[key]
+		fullKey := key
+		if keyPath != "" {
+			fullKey = keyPath + "." + key
+		}
+
+		i, ok := fieldByKey(t, key)
+		if !ok {
+			errs = errors.Join(errs, fmt.Errorf("%s: %s: unknown key", file, fullKey))
+			continue
+		}
+		f := t.Field(i)
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+
+		if _, hasEnv := f.Tag.Lookup("env"); !hasEnv && isNested(f.Type) {
+			m, ok := value.(map[string]any)
+			if !ok {
+				errs = errors.Join(errs, &FieldError{Field: fieldPath, Key: file + ": " + fullKey, Err: errors.New("expected a table of values")})
+				continue
+			}
+			errs = errors.Join(errs, l.applyMap(nested(v.Field(i)), m, file, fullKey, fieldPath))
+			continue
+		}
+
+		if err := setAny(v.Field(i), value); err != nil {
+			errs = errors.Join(errs, &FieldError{Field: fieldPath, Key: file + ": " + fullKey, Err: err})
+			continue
+		}
+		l.sources[fieldPath] = Source{Kind: SourceFile, Key: file + ": " + fullKey}
+	}
+
+	return errs
+}
+
+// fieldByKey returns the index of the exported field of t matching key.
+func fieldByKey(t reflect.Type, key string) (int, bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if name, ok := f.Tag.Lookup("key"); ok {
+			if name == key {
+				return i, true
+			}
+			continue
+		}
+		if normalizeKey(f.Name) == normalizeKey(key) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// normalizeKey lower cases key and removes underscores and dashes.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+// setAny sets v to a value decoded from a configuration file.
+//
+// Lists and tables are applied item by item to slices and maps,
+// scalars are parsed like environment variables.
+func setAny(v reflect.Value, x any) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setAny(elem.Elem(), x); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	textual := v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType)
+	switch x := x.(type) {
+	case []any:
+		if textual || v.Kind() != reflect.Slice {
+			return errors.New("unexpected list")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(x), len(x))
+		for i, item := range x {
+			if err := setAny(slice.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+		return nil
+	case map[string]any:
+		if textual || v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			return errors.New("unexpected table")
+		}
+		m := reflect.MakeMap(v.Type())
+		for key, item := range x {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setAny(elem, item); err != nil {
+				return fmt.Errorf("key %s: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+		return nil
+	}
+
+	return setString(v, scalarString(x))
+}
+
+// scalarString formats a scalar decoded from a configuration file
+// as it would be written in an environment variable.
+func scalarString(x any) string {
+	switch x := x.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(x)
+	}
+}
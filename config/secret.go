@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// redacted replaces the value of secrets when printed.
+const redacted = "[REDACTED]"
+
+// Secret is a configuration value that is never printed.
+//
+// Formatting with fmt, logging with slog and marshalling to JSON or text
+// all yield [REDACTED] rather than the value. Use Value to get the value.
+//
+// Secrets are typically read from files, either named by an environment
+// variable with the _FILE suffix or mounted in the secrets directory, see
+// package documentation.
+type Secret struct {
+	value string
+}
+
+// NewSecret returns a secret holding value.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Value returns the secret value.
+func (s Secret) Value() string {
+	return s.value
+}
+
+// IsZero reports whether the secret is empty.
+func (s Secret) IsZero() bool {
+	return s.value == ""
+}
+
+// String returns [REDACTED], or an empty string if the secret is empty.
+func (s Secret) String() string {
+	if s.value == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// GoString implements [fmt.GoStringer] for the %#v verb.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("config.Secret(%q)", s.String())
+}
+
+// Format implements [fmt.Formatter], redacting the secret for all verbs.
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, s.GoString())
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", s.String())
+	default:
+		fmt.Fprint(f, s.String())
+	}
+}
+
+// LogValue implements [slog.LogValuer].
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (s *Secret) UnmarshalText(text []byte) error {
+	s.value = string(text)
+	return nil
+}
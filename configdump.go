@@ -0,0 +1,173 @@
+package grffr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"go.cph.dev/grffr/config"
+	"go.cph.dev/grffr/options"
+)
+
+// configDump is the effective configuration, annotated with the source
+// of each value.
+type configDump struct {
+	// Options is the configuration of grffr itself.
+	Options map[string]config.Value `json:"options"`
+
+	// Config is the application's own configuration.
+	Config map[string]config.Value `json:"config,omitempty"`
+}
+
+// configDump returns the effective configuration.
+func (a *App) configDump() configDump {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+
+	cfg := a.configuration
+	cfg.Config = nil
+
+	dump := configDump{
+		Options: config.Describe(&cfg, a.optionSources),
+	}
+	delete(dump.Options, "Config")
+
+	// ConfigFiles are structs of options, given by type only
+	files := dump.Options["ConfigFiles"]
+	paths := make([]string, 0, len(cfg.ConfigFiles))
+	for _, f := range cfg.ConfigFiles {
+		paths = append(paths, f.Path)
+	}
+	files.Value = paths
+	dump.Options["ConfigFiles"] = files
+	if a.config != nil {
+		dump.Config = config.Describe(a.config, a.configSources)
+	}
+
+	return dump
+}
+
+// configHandler writes the effective configuration as JSON.
+func (a *App) configHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(a.configDump())
+	}
+	return http.HandlerFunc(fn)
+}
+
+// PrintConfig writes the effective configuration to w as JSON, annotated
+// with the source of each value and with secrets redacted.
+//
+// It is meant to be called instead of Run, e.g. from a "config"
+// subcommand, and loads the application's own configuration if Run has
+// not. Invalid configuration is returned as an error, after the
+// configuration has been written.
+func (a *App) PrintConfig(w io.Writer) error {
+	var configErr error
+	a.configMu.Lock()
+	if a.configuration.Config != nil && a.config == nil {
+		a.configSources = config.Sources{}
+		configErr = a.loadConfig(a.configuration.Config, a.configSources)
+		a.config = a.configuration.Config
+	}
+	a.configMu.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(a.configDump()); err != nil {
+		return fmt.Errorf("writing configuration: %w", err)
+	}
+
+	if err := errors.Join(a.envErr, configErr); err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	return nil
+}
+
+// configurationSources compares the configuration with defaults applied,
+// with environment variables applied and with options applied, returning
+// the source of each value.
+func configurationSources(defaults, env, cfg options.Configuration) config.Sources {
+	sources := config.Sources{}
+	compareSources(sources, "",
+		reflect.ValueOf(defaults),
+		reflect.ValueOf(env),
+		reflect.ValueOf(cfg),
+	)
+
+	return sources
+}
+
+func compareSources(sources config.Sources, path string, defaults, env, cfg reflect.Value) {
+	t := cfg.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			compareSources(sources, fieldPath, defaults.Field(i), env.Field(i), cfg.Field(i))
+			continue
+		}
+
+		switch {
+		case !sameValue(env.Field(i), cfg.Field(i)):
+			sources[fieldPath] = config.Source{Kind: config.SourceOption}
+		case !sameValue(defaults.Field(i), env.Field(i)):
+			sources[fieldPath] = config.Source{Kind: config.SourceEnv}
+		default:
+			sources[fieldPath] = config.Source{Kind: config.SourceDefault}
+		}
+	}
+}
+
+// sameValue reports whether a and b are the same value.
+//
+// Functions, pointers, maps and channels are compared by address, so
+// values replaced by options are detected even if they are not comparable.
+func sameValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Func, reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return a.Elem().Type() == b.Elem().Type() && sameValue(a.Elem(), b.Elem())
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		fallthrough
+	case reflect.Array:
+		for i := range a.Len() {
+			if !sameValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if !sameValue(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Equal(b)
+	}
+}
@@ -0,0 +1,100 @@
+package grffr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Dependent is a component depending on other components.
+//
+// DependsOn returns the names of the components (see [Named]) that must be
+// initialised and started before this component, and stopped after it.
+type Dependent interface {
+	DependsOn() []string
+}
+
+// sortComponents orders components so that every component comes after
+// the components it depends on.
+//
+// Components without dependencies keep their relative insertion order.
+// An error is returned if names are duplicated, a dependency is missing
+// or the dependencies form a cycle.
+func sortComponents(components []Component) ([]Component, error) {
+	var errs error
+
+	byName := make(map[string]int, len(components))
+	for i, c := range components {
+		named, ok := c.(Named)
+		if !ok {
+			continue
+		}
+		name := named.Name()
+		if _, exists := byName[name]; exists {
+			errs = errors.Join(errs, fmt.Errorf("component name %q used more than once", name))
+			continue
+		}
+		byName[name] = i
+	}
+
+	for c := range slices.Values(components) {
+		dependent, ok := c.(Dependent)
+		if !ok {
+			continue
+		}
+		for dep := range slices.Values(dependent.DependsOn()) {
+			if _, exists := byName[dep]; !exists {
+				errs = errors.Join(errs, fmt.Errorf("component %s depends on unknown component %q", componentName(c), dep))
+			}
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(components))
+	sorted := make([]Component, 0, len(components))
+	var path []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		c := components[i]
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			start := slices.Index(path, componentName(c))
+			cycle := append(slices.Clone(path[start:]), componentName(c))
+			return fmt.Errorf("component dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, componentName(c))
+		if dependent, ok := c.(Dependent); ok {
+			for dep := range slices.Values(dependent.DependsOn()) {
+				if err := visit(byName[dep]); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		sorted = append(sorted, c)
+
+		return nil
+	}
+
+	for i := range components {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
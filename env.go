@@ -0,0 +1,77 @@
+package grffr
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.cph.dev/grffr/config"
+	"go.cph.dev/grffr/options"
+)
+
+// environment holds the settings grffr reads from environment variables.
+//
+// Pointer fields are nil when not set, leaving defaults in place.
+type environment struct {
+	// Env is the environment the application runs in, e.g. "production".
+	Env string `env:"ENV" default:"development"`
+
+	HTTPAddr              *string        `env:"HTTP_ADDR"`
+	HTTPPort              *uint16        `env:"HTTP_PORT"`
+	HTTPReadTimeout       *time.Duration `env:"HTTP_READ_TIMEOUT"`
+	HTTPReadHeaderTimeout *time.Duration `env:"HTTP_READ_HEADER_TIMEOUT"`
+	HTTPWriteTimeout      *time.Duration `env:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout       *time.Duration `env:"HTTP_IDLE_TIMEOUT"`
+	HTTPMaxHeaderBytes    *int           `env:"HTTP_MAX_HEADER_BYTES"`
+	AdminAddr             *string        `env:"ADMIN_ADDR"`
+	LogLevel              *slog.Level    `env:"LOG_LEVEL"`
+
+	OTel struct {
+		SDKDisabled        bool   `env:"SDK_DISABLED"`
+		TracesExporter     string `env:"TRACES_EXPORTER"`
+		TracesFile         string `env:"TRACES_FILE" default:"traces.jsonl"`
+		OTLPEndpoint       string `env:"EXPORTER_OTLP_ENDPOINT"`
+		OTLPTracesEndpoint string `env:"EXPORTER_OTLP_TRACES_ENDPOINT"`
+	} `prefix:"OTEL_"`
+}
+
+// loadEnvironment reads grffr's settings from environment variables.
+//
+// All missing and invalid values are reported in the returned error.
+func loadEnvironment() (environment, error) {
+	var env environment
+	err := config.Load(&env)
+
+	return env, err
+}
+
+// apply overrides the configuration with the values set in the environment.
+//
+//   - HTTP_ADDR sets the HTTP server address, e.g. "127.0.0.1:8080".
+//   - HTTP_PORT sets the HTTP server port if HTTP_ADDR is not set.
+//   - HTTP_READ_TIMEOUT, HTTP_READ_HEADER_TIMEOUT, HTTP_WRITE_TIMEOUT
+//     and HTTP_IDLE_TIMEOUT set the HTTP server timeouts, e.g. "30s".
+//   - HTTP_MAX_HEADER_BYTES sets the maximum size of request headers.
+//   - ADMIN_ADDR sets the admin HTTP server address, e.g. "127.0.0.1:9090".
+//
+// LOG_LEVEL is not applied, as the level given by option takes precedence
+// over it, see [App.configuredLogLevel].
+func (env environment) apply(cfg *options.Configuration) {
+	set(&cfg.HTTPAddr, env.HTTPAddr)
+	if env.HTTPAddr == nil && env.HTTPPort != nil {
+		cfg.HTTPAddr = fmt.Sprintf(":%d", *env.HTTPPort)
+	}
+	set(&cfg.HTTPTimeouts.Read, env.HTTPReadTimeout)
+	set(&cfg.HTTPTimeouts.ReadHeader, env.HTTPReadHeaderTimeout)
+	set(&cfg.HTTPTimeouts.Write, env.HTTPWriteTimeout)
+	set(&cfg.HTTPTimeouts.Idle, env.HTTPIdleTimeout)
+	set(&cfg.HTTPMaxHeaderBytes, env.HTTPMaxHeaderBytes)
+	set(&cfg.AdminAddr, env.AdminAddr)
+}
+
+// set dst to the value of v, if not nil.
+func set[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
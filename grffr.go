@@ -10,20 +10,26 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"go.cph.dev/grffr/config"
 	"go.cph.dev/grffr/data"
 	"go.cph.dev/grffr/logging"
 	"go.cph.dev/grffr/options"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 const (
 
-	// readinessDelay is the duration to wait before checking if the server is ready.
+	// readinessDelay is the default duration to wait after readiness has been
+	// flipped to unavailable before shutting down.
 	//
 	// It is expected that readiness checks are performed to determine if the application
 	// is ready for traffic. The application will wait up til this duration with
@@ -31,12 +37,28 @@ const (
 	// to a new healthy instance.
 	readinessDelay = 5 * time.Second
 
-	// shutdownDuration is the duration to wait for components to shutdown.
+	// shutdownDuration is the default duration to wait for the HTTP servers
+	// and components to shutdown.
 	shutdownDuration = 15 * time.Second
+
+	// flushDuration is the default duration to wait for telemetry to be flushed.
+	flushDuration = 5 * time.Second
+
+	// startupTimeout is the default duration to wait for components to become ready.
+	startupTimeout = 30 * time.Second
+
+	// healthCheckTimeout is the default duration to wait for a component health check.
+	healthCheckTimeout = 2 * time.Second
+
+	// httpAddr is the default address of the HTTP server.
+	//
+	// A non-privileged port allows the application to run as non-root.
+	httpAddr = ":8080"
 )
 
 var (
-	version = "v0.0.3"
+	// grffrVersion is the version of the grffr framework.
+	grffrVersion = "v0.0.3"
 )
 
 //go:embed banner.txt
@@ -46,23 +68,86 @@ var banner string
 //
 // Defaults are applied before options, and can be overrriden or entirely disabled.
 func New(opts ...options.Option) *App {
-	app := App{}
+	app := App{
+		drainPolled: make(chan struct{}, 1),
+		router:      chi.NewMux(),
+		adminRouter: chi.NewMux(),
+
+		// Logging and telemetry are set up when initialised, until then
+		// the router can be served, e.g. in tests, with the defaults.
+		logger:         slog.Default(),
+		tracerProvider: noop.NewTracerProvider(),
+		tracer:         noop.NewTracerProvider().Tracer(tracerName),
+	}
 
 	slog.Debug("Setting defaults.")
 	cfg := options.Configuration{
 		Banner:           true,
+		StartupTimeout:   startupTimeout,
+		DrainDelay:       readinessDelay,
 		StartupHandler:   app.defaultStartupHandler(),
 		ReadinessHandler: app.defaultReadinessHandler(),
 		LivenessHandler:  app.defaultLivenessHandler(),
 		StatusHandler:    app.defaultStatusHandler(),
+		HealthPrefix:     "/.well-known/health",
+		StartupPaths:     []string{"/startup"},
+		LivenessPaths:    []string{"/alive"},
+		ReadinessPaths:   []string{"/ready"},
+		StatusPaths:      []string{"/status"},
+		VersionHandler:   app.defaultVersionHandler(),
+		VersionPaths:     []string{"/.well-known/version"},
+
+		HealthCheckTimeout: healthCheckTimeout,
+
+		ShutdownTimeouts: options.ShutdownTimeouts{
+			HTTP:       shutdownDuration,
+			Components: shutdownDuration,
+			Telemetry:  flushDuration,
+		},
+
+		HTTPAddr: httpAddr,
+		HTTPTimeouts: options.HTTPTimeouts{
+			Read:       2 * time.Minute,
+			ReadHeader: 5 * time.Second,
+			Write:      time.Minute,
+			Idle:       time.Minute,
+		},
+		HTTPMaxHeaderBytes: http.DefaultMaxHeaderBytes,
+
+		NotFoundHandler:         notFoundHandler(),
+		MethodNotAllowedHandler: methodNotAllowedHandler(),
+
+		RequestIDHeader: requestIDHeader,
 	}
 
+	defaults := cfg
+
+	slog.Debug("Reading environment.")
+	app.environment, app.envErr = loadEnvironment()
+	app.environment.apply(&cfg)
+	fromEnv := cfg
+
 	slog.Debug("Applying options.")
 	for _, opt := range opts {
 		opt(&cfg)
 	}
 
 	app.configuration = cfg
+	app.optionSources = configurationSources(defaults, fromEnv, cfg)
+	app.buildInfo = readBuildInfo(cfg.ServiceName, cfg.ServiceVersion)
+
+	// Middlewares must be registered before any routes
+	app.router.Use(
+		app.identifyRequests,
+		app.traceRequests,
+		app.measureRequests,
+		app.logRequests,
+		app.recoverRequests,
+	)
+	app.adminRouter.Use(
+		app.identifyRequests,
+		app.recoverRequests,
+	)
 
 	return &app
 }
@@ -72,28 +157,60 @@ func New(opts ...options.Option) *App {
 type App struct {
 	debug          bool
 	logger         *slog.Logger
+	logLevel       runtimeLevel
 	tracer         trace.Tracer
+	tracerProvider trace.TracerProvider
+	tracerShutdown func(context.Context) error
+	tracesFile     *os.File
+	meterProvider  metric.MeterProvider
+	meterShutdown  func(context.Context) error
+	metrics        *metrics
 	sql            data.SQL
 	startedAt      time.Time
 	configuration  options.Configuration
+	optionSources  config.Sources
+	buildInfo      BuildInfo
+	environment    environment
+	envErr         error
+	isStarted      atomic.Bool
 	isShuttingDown atomic.Bool
+	drainPolls     atomic.Int64
+	drainPolled    chan struct{}
 	httpServer     http.Server
+	router         *chi.Mux
+	adminServer    *http.Server
+	adminRouter    *chi.Mux
 	components     []Component
+	componentKeys  []string
+	supervisorsMu  sync.Mutex
+	supervisors    []*supervisor
+	reloadMu       sync.Mutex
+	configMu       sync.RWMutex
+	config         any
+	configSources  config.Sources
 }
 
+// Run initialises and runs the application until it is shut down.
+//
+// Use [App.PrintConfig] instead to print the effective configuration,
+// e.g. from a subcommand.
 func (a *App) Run() {
 	if a.configuration.Banner {
-		fmt.Printf(banner, version)
+		fmt.Printf(banner, grffrVersion)
+		fmt.Printf("  %s %s", a.buildInfo.Name, a.buildInfo.Version)
+		if a.buildInfo.Revision != "" {
+			fmt.Printf(" (%s)", a.buildInfo.ReleaseID())
+		}
+		fmt.Print("\n\n")
 	}
 	defer func() {
 		if err := recover(); err != nil {
-			slog.Error("Panic, terminating program.", "error", err)
+			slog.Error("Panic, terminating program.", logging.Panic(err, debug.Stack()))
 			os.Exit(1)
 		}
 		slog.Info("Shutdown complete. Ktxb.")
 	}()
 
-	// TODO: Support for start-up deadline?
 	ctx := context.Background()
 
 	a.startedAt = time.Now()
@@ -101,6 +218,10 @@ func (a *App) Run() {
 	err := a.init(ctx)
 	if err != nil {
 		slog.Error("Initialising application", "error", err)
+		// Telemetry may have been set up before failing
+		if err := a.flushTelemetry(ctx); err != nil {
+			slog.Error("Flushing telemetry", "error", err)
+		}
 		os.Exit(1)
 	}
 
@@ -114,17 +235,48 @@ func (a *App) Run() {
 func (a *App) init(ctx context.Context) error {
 	a.debug = a.configuration.Debug
 
-	// TODO: If Debug is not enabled then log level should be adjusted.
+	a.logLevel.configure(a.configuredLogLevel(a.environment))
 	if a.configuration.Logger != nil {
 		a.logger = a.configuration.Logger
 	} else {
-		a.logger = logging.Configure()
+		a.logger = logging.Configure(logging.Config{
+			Env:             a.environment.Env,
+			Attrs:           a.buildInfo.logAttrs(),
+			TraceAttributes: a.configuration.LogTraceAttributes,
+			Level:           &a.logLevel,
+		})
 	}
 	slog.SetDefault(a.logger)
 
+	// Report all missing and invalid configuration at once
+	var configErr error
+	if a.configuration.Config != nil {
+		a.configSources = config.Sources{}
+		configErr = a.loadConfig(a.configuration.Config, a.configSources)
+		a.config = a.configuration.Config
+	}
+	if err := errors.Join(a.envErr, configErr); err != nil {
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	if err := a.initTracing(ctx); err != nil {
+		return fmt.Errorf("initialising tracing: %w", err)
+	}
+	if err := a.initMetrics(ctx); err != nil {
+		return fmt.Errorf("initialising metrics: %w", err)
+	}
+
+	components, err := sortComponents(a.components)
+	if err != nil {
+		return fmt.Errorf("ordering components: %w", err)
+	}
+	a.components = components
+	a.componentKeys = componentKeys(components)
+
 	return errors.Join(
 		a.initComponents(ctx),
 		a.initWebServer(),
+		a.initAdminServer(),
 	)
 }
 
@@ -159,17 +311,17 @@ func (a *App) run(ctx context.Context) error {
 		// Block until a signal is received, then initate shutdown
 		<-ctx.Done()
 		stop()
+		wasStarted := a.isStarted.Load()
 		a.isShuttingDown.Store(true)
 		slog.InfoContext(ctx, "Received shut down signal, shutting down application.")
 
-		// TODO: Wait or enure that readiness has been signalled.
-		// Either Sleep(readinessDelay) or make some channel communication to
-		// signal that the Readiness end-point has been called and responded.
-
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownDuration)
-		defer cancel()
+		// Give proxies/load balancers a chance to observe that the application
+		// is no longer ready before requests are drained.
+		if wasStarted {
+			a.drain()
+		}
 
-		err := a.shutdown(shutdownCtx)
+		err := a.shutdown(context.WithoutCancel(ctx))
 		// // Cancel any inflight requests that might still be processing ?
 		// inflightCancel()
 		if err != nil {
@@ -177,21 +329,55 @@ func (a *App) run(ctx context.Context) error {
 		}
 	}()
 
-	// Start components
-	startUpCtx := context.WithoutCancel(ctx)
-	a.startComponents(startUpCtx, &exit)
-
-	// Start web server
+	// Reload configuration on SIGHUP
 	exit.Add(1)
 	go func() {
 		defer exit.Done()
+		a.reloadOnHangup(ctx)
+	}()
+
+	// Start admin server first, so probes are answered during startup
+	if a.adminServer != nil {
+		exit.Add(1)
+		go func() {
+			defer exit.Done()
+
+			slog.Info("Starting admin HTTP server...", slog.String("addr", a.adminServer.Addr))
+			err := a.adminServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				addErr(fmt.Errorf("admin HTTP server stopped with unexpected error: %w", err))
+			}
+		}()
+	}
 
-		slog.Info("Starting HTTP server...")
-		err := a.httpServer.ListenAndServe()
-		if err != nil && err != http.ErrServerClosed {
-			addErr(fmt.Errorf("HTTP server stopped with unexpected error: %w", err))
+	// Start components and wait for them to become ready
+	escalate := func(err error) {
+		addErr(err)
+		stop()
+	}
+	err := a.startComponents(ctx, &exit, escalate)
+	if err != nil {
+		// A shut down signal during startup is not a failure
+		if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+			slog.InfoContext(ctx, "Startup interrupted.", logging.Error(err))
+		} else {
+			addErr(fmt.Errorf("starting components: %w", err))
 		}
-	}()
+		stop()
+	} else {
+		// Start web server
+		exit.Add(1)
+		go func() {
+			defer exit.Done()
+
+			slog.Info("Starting HTTP server...", slog.String("addr", a.httpServer.Addr))
+			err := a.httpServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				addErr(fmt.Errorf("HTTP server stopped with unexpected error: %w", err))
+			}
+		}()
+		a.isStarted.Store(true)
+	}
 
 	// Wait for all components to exit
 	exit.Wait()
@@ -200,15 +386,93 @@ func (a *App) run(ctx context.Context) error {
 	return e
 }
 
+// drain waits for readiness to be observed as unavailable.
+//
+// It waits for the configured drain delay, or until the readiness end-point
+// has been polled the configured number of times, whichever comes first.
+func (a *App) drain() {
+	delay := a.configuration.DrainDelay
+	if delay <= 0 {
+		return
+	}
+
+	start := time.Now()
+	slog.Info("Draining readiness.",
+		slog.Duration("delay", delay),
+		slog.Int("polls", a.configuration.DrainPolls),
+	)
+	defer func() {
+		slog.Info("Drained readiness.",
+			slog.Duration("duration", time.Since(start)),
+			slog.Int64("polls", a.drainPolls.Load()),
+		)
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	polls := int64(a.configuration.DrainPolls)
+	for polls <= 0 || a.drainPolls.Load() < polls {
+		select {
+		case <-timer.C:
+			return
+		case <-a.drainPolled:
+		}
+	}
+}
+
+// readinessProbe wraps the readiness handler, so readiness is reported
+// unavailable once shutting down and polls during drain are counted.
+func (a *App) readinessProbe(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !a.isShuttingDown.Load() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, "Shutting down"))
+		a.drainPolls.Add(1)
+		select {
+		case a.drainPolled <- struct{}{}:
+		default:
+		}
+	}
+	return http.HandlerFunc(fn)
+}
+
 // shutdown components and services.
 //
 // Services are shutdown first to ensure request drain, then
-// components are stopped.
+// components are stopped. The admin server is shutdown after,
+// so probes are answered for as long as possible. Finally telemetry
+// is flushed.
 func (a *App) shutdown(ctx context.Context) error {
-	err := errors.Join(
-		a.httpServer.Shutdown(ctx),
-		a.stopComponents(ctx),
-	)
+	timeouts := a.configuration.ShutdownTimeouts
+
+	start := time.Now()
+	slog.InfoContext(ctx, "Shutting down HTTP server.")
+	httpCtx, cancel := withTimeout(ctx, timeouts.HTTP)
+	httpErr := a.httpServer.Shutdown(httpCtx)
+	cancel()
+	slog.InfoContext(ctx, "HTTP server shut down.", slog.Duration("duration", time.Since(start)))
+
+	start = time.Now()
+	componentsCtx, cancel := withTimeout(ctx, timeouts.Components)
+	componentsErr := a.stopComponents(componentsCtx)
+	cancel()
+	slog.InfoContext(ctx, "Components stopped.", slog.Duration("duration", time.Since(start)))
+
+	var adminErr error
+	if a.adminServer != nil {
+		adminCtx, cancel := withTimeout(ctx, timeouts.HTTP)
+		adminErr = a.adminServer.Shutdown(adminCtx)
+		cancel()
+		slog.InfoContext(ctx, "Admin HTTP server shut down.")
+	}
+
+	// Flush telemetry last, so spans from shutting down are included
+	telemetryErr := a.flushTelemetry(ctx)
+
+	err := errors.Join(httpErr, componentsErr, adminErr, telemetryErr)
 	if err != nil {
 		slog.ErrorContext(ctx, "Shutdown", "error", err)
 		return err
@@ -217,11 +481,30 @@ func (a *App) shutdown(ctx context.Context) error {
 	return nil
 }
 
-// portAvailable checks if the given port is available for use.
+// flushTelemetry flushes and shuts down tracing and metrics, waiting at
+// most the telemetry shutdown timeout.
+func (a *App) flushTelemetry(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, a.configuration.ShutdownTimeouts.Telemetry)
+	defer cancel()
+
+	return errors.Join(a.shutdownTracing(ctx), a.shutdownMetrics(ctx))
+}
+
+// withTimeout returns a context done after d, or only when ctx is done if
+// d is zero or less.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, d)
+}
+
+// addrAvailable checks if the given address is available for use.
 //
 // Error is nil if available, otherwise error contains the underlying reason.
-func portAvailable(port int) error {
-	l, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
+func addrAvailable(addr string) error {
+	l, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
 	}
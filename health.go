@@ -1,9 +1,13 @@
 package grffr
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -17,6 +21,29 @@ type Healthchecker interface {
 	Healthcheck() Health
 }
 
+// HealthScope is a set of health end-points affected by a health check.
+type HealthScope int
+
+const (
+	// HealthScopeReadiness makes a DOWN check fail the readiness probe.
+	HealthScopeReadiness HealthScope = 1 << iota
+
+	// HealthScopeLiveness makes a DOWN check fail the liveness probe.
+	HealthScopeLiveness
+
+	// HealthScopeStatus only reports the check in the status end-point.
+	HealthScopeStatus HealthScope = 0
+)
+
+// HealthScoped is a Healthchecker declaring which health end-points
+// its health check affects.
+//
+// All health checks are reported in the status end-point. Components
+// implementing Healthchecker but not HealthScoped affect readiness.
+type HealthScoped interface {
+	HealthScope() HealthScope
+}
+
 type Health struct {
 	Status    HealthStatus   `json:"status"`
 	Uptime    string         `json:"uptime,omitempty"`
@@ -41,10 +68,115 @@ const (
 	HealthStatusDegraded = "DEGRADED"
 )
 
+// worstHealthStatus rolls up statuses into a single status.
+//
+// DOWN takes precedence over DEGRADED, which takes precedence over UP.
+// Unknown statuses are considered DOWN. No statuses are UP.
+func worstHealthStatus(statuses ...HealthStatus) HealthStatus {
+	worst := HealthStatus(HealthStatusUp)
+	for status := range slices.Values(statuses) {
+		switch status {
+		case HealthStatusOK, HealthStatusUp:
+		case HealthStatusDegraded:
+			if worst != HealthStatusDown {
+				worst = HealthStatusDegraded
+			}
+		default:
+			worst = HealthStatusDown
+		}
+	}
+
+	return worst
+}
+
+// healthChecks runs the health checks of all components affecting scope
+// concurrently and returns the results keyed by component key,
+// see [componentKeys].
+//
+// A zero scope runs all health checks. Checks not reporting within the
+// configured timeout are DOWN.
+func (a *App) healthChecks(scope HealthScope) map[string]Health {
+	var (
+		l       sync.Mutex
+		wg      sync.WaitGroup
+		results = map[string]Health{}
+	)
+	for i, c := range a.components {
+		checker, ok := c.(Healthchecker)
+		if !ok {
+			continue
+		}
+		checkScope := HealthScopeReadiness
+		if scoped, ok := c.(HealthScoped); ok {
+			checkScope = scoped.HealthScope()
+		}
+		if scope != 0 && checkScope&scope == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			health := runHealthCheck(checker, a.configuration.HealthCheckTimeout)
+			l.Lock()
+			defer l.Unlock()
+			results[a.componentKeys[i]] = health
+		}()
+	}
+	wg.Wait()
+	a.recordHealth(context.Background(), results)
+
+	return results
+}
+
+// runHealthCheck returns the health of checker, or DOWN if it
+// does not report within timeout.
+//
+// A timeout of zero or less waits for the check indefinitely.
+func runHealthCheck(checker Healthchecker, timeout time.Duration) Health {
+	if timeout <= 0 {
+		return checker.Healthcheck()
+	}
+
+	result := make(chan Health, 1)
+	go func() {
+		result <- checker.Healthcheck()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case health := <-result:
+		return health
+	case <-timer.C:
+		return Health{
+			Status: HealthStatusDown,
+			Details: map[string]any{
+				"error": fmt.Sprintf("health check timed out after %s", timeout),
+			},
+		}
+	}
+}
+
+// healthStatus rolls up the status of health check results.
+func healthStatus(results map[string]Health) HealthStatus {
+	statuses := []HealthStatus{}
+	for health := range maps.Values(results) {
+		statuses = append(statuses, health.Status)
+	}
+
+	return worstHealthStatus(statuses...)
+}
+
 func (a *App) defaultLivenessHandler() http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		if a.isShuttingDown.Load() {
-			http.Error(w, "Shutting down", http.StatusServiceUnavailable)
+			WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, "Shutting down"))
+			return
+		}
+		if healthStatus(a.healthChecks(HealthScopeLiveness)) == HealthStatusDown {
+			WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, HealthStatusDown))
 			return
 		}
 		fmt.Fprintln(w, HealthStatusOK)
@@ -59,7 +191,19 @@ func (a *App) defaultStartupHandler() http.Handler {
 func (a *App) defaultReadinessHandler() http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		if a.isShuttingDown.Load() {
-			http.Error(w, "Shutting down", http.StatusServiceUnavailable)
+			WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, "Shutting down"))
+			return
+		}
+		if !a.isStarted.Load() {
+			WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, "Starting up"))
+			return
+		}
+		status := worstHealthStatus(
+			healthStatus(a.healthChecks(HealthScopeReadiness)),
+			a.supervisionStatus(true),
+		)
+		if status == HealthStatusDown {
+			WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, HealthStatusDown))
 			return
 		}
 		fmt.Fprintln(w, HealthStatusUp)
@@ -70,27 +214,53 @@ func (a *App) defaultReadinessHandler() http.Handler {
 func (a *App) defaultStatusHandler() http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		if a.isShuttingDown.Load() {
-			http.Error(w, "Shutting down", http.StatusServiceUnavailable)
+			WriteProblem(w, r, NewProblem(http.StatusServiceUnavailable, "Shutting down"))
 			return
 		}
 
+		checks := a.healthChecks(HealthScopeStatus)
+		components := a.componentStatuses()
+		for name, health := range checks {
+			status := components[name]
+			status.Health = &health
+			components[name] = status
+		}
+
 		now := time.Now()
 		uptime := time.Since(a.startedAt)
 		uptime = uptime.Round(time.Second)
 		health := Health{
-			Status:    HealthStatusUp,
+			Status:    worstHealthStatus(healthStatus(checks), a.supervisionStatus(false)),
 			Uptime:    uptime.String(),
 			UptimeSec: int64(uptime.Seconds()),
-			Details:   map[string]any{},
+			Details: map[string]any{
+				"components": components,
+			},
 			Meta: HealthMeta{
 				Timestamp:        now.Truncate(time.Millisecond),
 				TimestampUnixMs:  now.UnixMilli(),
 				TimestampUnixSec: now.Unix(),
-				// TODO: Add Version
+				Version:          a.buildInfo.Version,
 			},
 		}
+
+		code := http.StatusOK
+		if health.Status == HealthStatusDown {
+			code = http.StatusServiceUnavailable
+		}
+
+		// Respond in the Health Check Response Format for HTTP APIs
+		// if requested, otherwise use the Health format.
+		w.Header().Add("Vary", "Accept")
+		if preferredMediaType(r, "application/json", MediaTypeHealthJSON) == MediaTypeHealthJSON {
+			w.Header().Set("Content-Type", MediaTypeHealthJSON)
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(healthResponse(health, components, a.buildInfo))
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(code)
 		json.NewEncoder(w).Encode(health)
 	}
 	return http.HandlerFunc(fn)
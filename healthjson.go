@@ -0,0 +1,105 @@
+package grffr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// MediaTypeHealthJSON is the media type of the Health Check Response
+// Format for HTTP APIs.
+const MediaTypeHealthJSON = "application/health+json"
+
+// HealthResponse is a health check response in the Health Check Response
+// Format for HTTP APIs, as described by draft-inadarei-api-health-check.
+//
+// The status end-point responds in this format when requested with
+// Accept: application/health+json.
+type HealthResponse struct {
+	Status      string                         `json:"status"`
+	Version     string                         `json:"version,omitempty"`
+	ReleaseID   string                         `json:"releaseId,omitempty"`
+	Notes       []string                       `json:"notes,omitempty"`
+	Output      string                         `json:"output,omitempty"`
+	ServiceID   string                         `json:"serviceId,omitempty"`
+	Description string                         `json:"description,omitempty"`
+	Checks      map[string][]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult is a single measurement of a HealthResponse.
+//
+// Checks are keyed by "{componentName}:{measurementName}".
+type HealthCheckResult struct {
+	ComponentID   string    `json:"componentId,omitempty"`
+	ComponentType string    `json:"componentType,omitempty"`
+	ObservedValue any       `json:"observedValue,omitempty"`
+	ObservedUnit  string    `json:"observedUnit,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	Time          time.Time `json:"time,omitzero"`
+	Output        string    `json:"output,omitempty"`
+}
+
+const (
+	HealthResponseStatusPass = "pass"
+	HealthResponseStatusWarn = "warn"
+	HealthResponseStatusFail = "fail"
+)
+
+// healthResponseStatus maps a HealthStatus to pass, warn or fail.
+func healthResponseStatus(status HealthStatus) string {
+	switch worstHealthStatus(status) {
+	case HealthStatusUp:
+		return HealthResponseStatusPass
+	case HealthStatusDegraded:
+		return HealthResponseStatusWarn
+	default:
+		return HealthResponseStatusFail
+	}
+}
+
+// healthResponse converts a status report to the health+json format.
+func healthResponse(health Health, components map[string]ComponentStatus, info BuildInfo) HealthResponse {
+	now := health.Meta.Timestamp
+	response := HealthResponse{
+		Status:    healthResponseStatus(health.Status),
+		Version:   info.Version,
+		ReleaseID: info.ReleaseID(),
+		ServiceID: info.Name,
+		Checks: map[string][]HealthCheckResult{
+			"uptime": {{
+				ComponentType: "system",
+				ObservedValue: health.UptimeSec,
+				ObservedUnit:  "s",
+				Status:        HealthResponseStatusPass,
+				Time:          now,
+			}},
+		},
+	}
+
+	for name := range slices.Values(slices.Sorted(maps.Keys(components))) {
+		component := components[name]
+		response.Checks[name+":restarts"] = []HealthCheckResult{{
+			ComponentType: "component",
+			ObservedValue: component.Restarts,
+			Status:        healthResponseStatus(component.State.healthStatus()),
+			Time:          now,
+			Output:        component.LastError,
+		}}
+		if component.Health == nil {
+			continue
+		}
+
+		result := HealthCheckResult{
+			ComponentType: "component",
+			Status:        healthResponseStatus(component.Health.Status),
+			Time:          now,
+		}
+		if err, ok := component.Health.Details["error"]; ok {
+			result.Output = fmt.Sprint(err)
+		}
+		response.Checks[name+":health"] = []HealthCheckResult{result}
+	}
+
+	return response
+}
@@ -1,8 +1,40 @@
 package logging
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
 
 // Error returns an slog attribute with the error value.
 func Error(err error) slog.Attr {
 	return slog.Any("error", err)
 }
+
+// Panic returns an slog attribute with the recovered panic value and the
+// stack of the panicking goroutine, as returned by [runtime/debug.Stack].
+//
+// The stack is logged as a list of frames, "function file:line".
+func Panic(v any, stack []byte) slog.Attr {
+	return slog.Group("panic",
+		slog.String("value", fmt.Sprint(v)),
+		slog.Any("stack", stackFrames(stack)),
+	)
+}
+
+// stackFrames parses a goroutine stack trace into frames.
+func stackFrames(stack []byte) []string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "goroutine ") {
+		lines = lines[1:]
+	}
+
+	frames := make([]string, 0, len(lines)/2)
+	for i := 0; i+1 < len(lines); i += 2 {
+		function := strings.TrimSpace(lines[i])
+		location, _, _ := strings.Cut(strings.TrimSpace(lines[i+1]), " +0x")
+		frames = append(frames, function+" "+location)
+	}
+
+	return frames
+}
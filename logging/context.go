@@ -3,6 +3,8 @@ package logging
 import (
 	"context"
 	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Code in this file is inspired by the article written by Ayooluwa Isaiah:
@@ -17,15 +19,26 @@ const (
 
 type ContextHandler struct {
 	slog.Handler
+
+	// Trace names the attributes of the active span added to records.
+	//
+	// Default is DefaultTraceAttributes.
+	Trace *TraceAttributes
 }
 
-// Handle adds contextual attributes to the Record before calling the underlying
-// handler.
+// Handle adds contextual attributes and the active span to the Record before
+// calling the underlying handler.
 func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
 	if attrs, ok := ctx.Value(SlogFields).([]slog.Attr); ok {
 		r.AddAttrs(attrs...)
 	}
 
+	traceAttrs := DefaultTraceAttributes
+	if h.Trace != nil {
+		traceAttrs = *h.Trace
+	}
+	r.AddAttrs(traceAttrs.attrs(trace.SpanContextFromContext(ctx))...)
+
 	// Call the underlying handler
 	return h.Handler.Handle(ctx, r)
 }
@@ -39,11 +52,11 @@ func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
 // WithAttrs returns a new [ContextHandler] whose attributes consists
 // of h's attributes followed by attrs.
 func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs), Trace: h.Trace}
 }
 
 func (h *ContextHandler) WithGroup(name string) slog.Handler {
-	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+	return &ContextHandler{Handler: h.Handler.WithGroup(name), Trace: h.Trace}
 }
 
 // AppendCtx adds an slog attribute to the provided context so that it will be
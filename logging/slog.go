@@ -8,25 +8,50 @@ import (
 	"github.com/lmittmann/tint"
 )
 
-// Configure sets up the logger based on the environment.
+// Config of the logger set up by Configure.
+type Config struct {
+	// Env is the environment the application runs in. Development,
+	// the default, gets pretty colored output.
+	Env string
+
+	// Attrs are added to the app group of every log record in production.
+	Attrs []slog.Attr
+
+	// TraceAttributes names the attributes of the active span added to
+	// every log record. Default is DefaultTraceAttributes.
+	TraceAttributes *TraceAttributes
+
+	// Level is the minimum level of records logged, e.g. a [slog.LevelVar]
+	// to change it at runtime. Default is debug in development and info
+	// otherwise.
+	Level slog.Leveler
+}
+
+// Configure sets up the logger based on the environment in cfg.
 //
 // Production gets JSON output, while development gets pretty colored output.
-func Configure() *slog.Logger {
+func Configure(cfg Config) *slog.Logger {
 	// TODO: This should be based on IsTTY
-	env := os.Getenv("ENV")
+	env := cfg.Env
 	if env == "" {
 		env = "development"
 	}
 
+	level := cfg.Level
+	if level == nil {
+		level = DefaultLevel(env)
+	}
+
 	var logger *slog.Logger
-	if env == "development" || env == "dev" {
+	if isDevelopment(env) {
 		tinted := tint.NewHandler(os.Stdout, &tint.Options{
 			AddSource:  true,
-			Level:      slog.LevelDebug,
+			Level:      level,
 			TimeFormat: time.TimeOnly,
 		})
 		handler := &ContextHandler{
 			Handler: tinted,
+			Trace:   cfg.TraceAttributes,
 		}
 		logger = slog.New(handler)
 		logger.Debug("Amazing logging configured.",
@@ -37,17 +62,34 @@ func Configure() *slog.Logger {
 	} else {
 		loggerOpts := &slog.HandlerOptions{
 			AddSource: true,
+			Level:     level,
 		}
 		jsonHandler := slog.NewJSONHandler(os.Stdout, loggerOpts)
 		h := &ContextHandler{
 			Handler: jsonHandler,
+			Trace:   cfg.TraceAttributes,
 		}
-		logger = slog.New(h).With(
-			slog.Group("app",
-				slog.String("env", env),
-				// slog.String("version", version),
-			))
+		attrs := []any{slog.String("env", env)}
+		for _, attr := range cfg.Attrs {
+			attrs = append(attrs, attr)
+		}
+		logger = slog.New(h).With(slog.Group("app", attrs...))
 	}
 
 	return logger
 }
+
+// DefaultLevel returns the default minimum level logged in env, debug in
+// development and info otherwise.
+func DefaultLevel(env string) slog.Level {
+	if isDevelopment(env) {
+		return slog.LevelDebug
+	}
+
+	return slog.LevelInfo
+}
+
+// isDevelopment reports whether env is a development environment.
+func isDevelopment(env string) bool {
+	return env == "" || env == "development" || env == "dev"
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceAttributes are the names of the attributes the active span is
+// logged with by the ContextHandler.
+//
+// Empty names are not logged.
+type TraceAttributes struct {
+	TraceID string
+	SpanID  string
+	Sampled string
+
+	// Decimal logs the lower 64 bits of the trace ID and the span ID as
+	// decimal numbers instead of hex.
+	Decimal bool
+
+	// TraceIDFormat formats the trace ID, overriding Decimal for it.
+	TraceIDFormat func(trace.TraceID) string
+}
+
+var (
+	// DefaultTraceAttributes follow the OpenTelemetry naming.
+	DefaultTraceAttributes = TraceAttributes{
+		TraceID: "trace_id",
+		SpanID:  "span_id",
+		Sampled: "trace_sampled",
+	}
+
+	// DatadogTraceAttributes follow the Datadog naming and ID format.
+	DatadogTraceAttributes = TraceAttributes{
+		TraceID: "dd.trace_id",
+		SpanID:  "dd.span_id",
+		Decimal: true,
+	}
+
+	// ElasticTraceAttributes follow the Elastic Common Schema naming.
+	ElasticTraceAttributes = TraceAttributes{
+		TraceID: "trace.id",
+		SpanID:  "span.id",
+	}
+)
+
+// GCPTraceAttributes follow the Google Cloud Logging naming and
+// trace format for the Google Cloud project projectID.
+func GCPTraceAttributes(projectID string) TraceAttributes {
+	return TraceAttributes{
+		TraceID: "logging.googleapis.com/trace",
+		SpanID:  "logging.googleapis.com/spanId",
+		Sampled: "logging.googleapis.com/trace_sampled",
+		TraceIDFormat: func(id trace.TraceID) string {
+			return "projects/" + projectID + "/traces/" + id.String()
+		},
+	}
+}
+
+// attrs returns the attributes of the span context, if valid.
+func (t TraceAttributes) attrs(sc trace.SpanContext) []slog.Attr {
+	if !sc.IsValid() {
+		return nil
+	}
+
+	traceID, spanID := sc.TraceID().String(), sc.SpanID().String()
+	if t.Decimal {
+		id := sc.TraceID()
+		traceID = strconv.FormatUint(binary.BigEndian.Uint64(id[8:]), 10)
+		sid := sc.SpanID()
+		spanID = strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10)
+	}
+	if t.TraceIDFormat != nil {
+		traceID = t.TraceIDFormat(sc.TraceID())
+	}
+
+	attrs := make([]slog.Attr, 0, 3)
+	if t.TraceID != "" {
+		attrs = append(attrs, slog.String(t.TraceID, traceID))
+	}
+	if t.SpanID != "" {
+		attrs = append(attrs, slog.String(t.SpanID, spanID))
+	}
+	if t.Sampled != "" {
+		attrs = append(attrs, slog.Bool(t.Sampled, sc.IsSampled()))
+	}
+
+	return attrs
+}
@@ -0,0 +1,190 @@
+package grffr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.cph.dev/grffr/logging"
+)
+
+// runtimeLevel is the log level of the default logger, which can be
+// overridden at runtime and reverted to the configured level.
+type runtimeLevel struct {
+	slog.LevelVar
+
+	mu         sync.Mutex
+	configured slog.Level
+	overridden bool
+	revert     *time.Timer
+	revertAt   time.Time
+
+	// generation is incremented on every change, so a revert timer
+	// firing late does not revert a newer override.
+	generation int
+}
+
+// configure sets the configured level, applied unless overridden.
+func (l *runtimeLevel) configure(level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.configured = level
+	if !l.overridden {
+		l.Set(level)
+	}
+}
+
+// override sets the level, reverting to the configured level after d
+// unless d is zero.
+func (l *runtimeLevel) override(level slog.Level, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stop()
+	l.overridden = true
+	l.Set(level)
+	if d > 0 {
+		generation := l.generation
+		l.revertAt = time.Now().Add(d)
+		l.revert = time.AfterFunc(d, func() {
+			l.reset(generation)
+		})
+	}
+}
+
+// reset reverts to the configured level, unless the level has been
+// changed since generation.
+func (l *runtimeLevel) reset(generation int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if generation != l.generation {
+		return
+	}
+	l.stop()
+	l.overridden = false
+	slog.Info("Reverting log level.", slog.String("level", l.configured.String()))
+	l.Set(l.configured)
+}
+
+// stop cancels a pending revert.
+func (l *runtimeLevel) stop() {
+	l.generation++
+	if l.revert != nil {
+		l.revert.Stop()
+		l.revert = nil
+	}
+	l.revertAt = time.Time{}
+}
+
+// logLevelState is the log level as returned by the admin server.
+type logLevelState struct {
+	Level      string    `json:"level"`
+	Configured string    `json:"configured"`
+	RevertAt   time.Time `json:"revert_at,omitzero"`
+}
+
+func (l *runtimeLevel) state() logLevelState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return logLevelState{
+		Level:      l.Level().String(),
+		Configured: l.configured.String(),
+		RevertAt:   l.revertAt,
+	}
+}
+
+// configuredLogLevel returns the log level from the configuration and env.
+//
+// Debug takes precedence, then a level given by option, then LOG_LEVEL.
+func (a *App) configuredLogLevel(env environment) slog.Level {
+	cfg := a.configuration
+	switch {
+	case cfg.Debug:
+		return slog.LevelDebug
+	case cfg.LogLevel != nil:
+		return *cfg.LogLevel
+	case env.LogLevel != nil:
+		return *env.LogLevel
+	default:
+		return logging.DefaultLevel(env.Env)
+	}
+}
+
+// errLogLevelUncontrolled is reported when the level of the logger given
+// with [options.WithLogger] is requested or changed.
+var errLogLevelUncontrolled = NewProblem(http.StatusConflict, "The log level of a custom logger cannot be controlled")
+
+// logLevelHandler returns the current log level.
+func (a *App) logLevelHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if a.configuration.Logger != nil {
+			WriteProblem(w, r, errLogLevelUncontrolled)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(a.logLevel.state())
+	}
+	return http.HandlerFunc(fn)
+}
+
+// setLogLevelHandler overrides the log level, e.g.
+//
+//	{"level": "debug", "revert_after": "10m"}
+//
+// reverting to the configured level after revert_after, if given.
+func (a *App) setLogLevelHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if a.configuration.Logger != nil {
+			WriteProblem(w, r, errLogLevelUncontrolled)
+			return
+		}
+
+		var req struct {
+			Level       string `json:"level"`
+			RevertAfter string `json:"revert_after"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			WriteProblem(w, r, fmt.Errorf("decoding request: %w", ErrBadRequest))
+			return
+		}
+
+		var (
+			level  slog.Level
+			revert time.Duration
+			errs   ValidationErrors
+		)
+		if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+			errs = append(errs, ValidationError{Field: "level", Detail: fmt.Sprintf("invalid level %q", req.Level)})
+		}
+		if req.RevertAfter != "" {
+			d, err := time.ParseDuration(req.RevertAfter)
+			if err != nil || d <= 0 {
+				errs = append(errs, ValidationError{Field: "revert_after", Detail: fmt.Sprintf("invalid duration %q", req.RevertAfter)})
+			}
+			revert = d
+		}
+		if len(errs) > 0 {
+			WriteProblem(w, r, errs)
+			return
+		}
+
+		a.logLevel.override(level, revert)
+		slog.InfoContext(r.Context(), "Log level changed.",
+			slog.String("level", level.String()),
+			slog.Duration("revert_after", revert),
+		)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(a.logLevel.state())
+	}
+	return http.HandlerFunc(fn)
+}
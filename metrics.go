@@ -0,0 +1,225 @@
+package grffr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// metrics recorded by the framework.
+type metrics struct {
+	requestDuration     metric.Float64Histogram
+	requestBodySize     metric.Int64Histogram
+	responseBodySize    metric.Int64Histogram
+	componentHealth     metric.Int64Gauge
+	panics              metric.Int64Counter
+	componentRestarts   metric.Int64ObservableCounter
+	componentState      metric.Int64ObservableGauge
+	componentStateNames []ComponentState
+}
+
+// initMetrics configures the meter provider and the framework's instruments.
+//
+// Unless a meter provider is given with [options.WithMeterProvider],
+// metrics are exposed in the Prometheus text format at /metrics on
+// the admin server, or on the HTTP server if the admin server is not
+// enabled, including Go runtime and process metrics.
+// Metrics are disabled if OTEL_SDK_DISABLED is "true".
+func (a *App) initMetrics(ctx context.Context) error {
+	switch {
+	case a.configuration.MeterProvider != nil:
+		a.meterProvider = a.configuration.MeterProvider
+	case a.environment.OTel.SDKDisabled:
+		slog.DebugContext(ctx, "Metrics disabled.")
+		a.meterProvider = noop.NewMeterProvider()
+	default:
+		res, err := a.newResource(ctx)
+		if err != nil {
+			return fmt.Errorf("creating metric resource: %w", err)
+		}
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+		exporter, err := otelprom.New(otelprom.WithRegisterer(registry))
+		if err != nil {
+			return fmt.Errorf("creating metric exporter: %w", err)
+		}
+
+		mp := sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(res),
+			sdkmetric.WithReader(exporter),
+		)
+		a.meterProvider = mp
+		a.meterShutdown = mp.Shutdown
+
+		// Like health checks, metrics are served by the admin server
+		// when enabled, keeping them off the public HTTP server.
+		handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+		if a.adminEnabled() {
+			a.adminRouter.Handle("GET /metrics", handler)
+		} else {
+			a.router.Handle("GET /metrics", handler)
+		}
+	}
+
+	meter := a.meterProvider.Meter(tracerName, metric.WithInstrumentationVersion(grffrVersion))
+	m := metrics{
+		componentStateNames: []ComponentState{
+			ComponentStateStarting,
+			ComponentStateRunning,
+			ComponentStateRestarting,
+			ComponentStateFailed,
+			ComponentStateStopped,
+		},
+	}
+
+	var err, errs error
+	m.requestDuration, err = meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10),
+	)
+	errs = errors.Join(errs, err)
+	m.requestBodySize, err = meter.Int64Histogram("http.server.request.body.size",
+		metric.WithDescription("Size of HTTP server request bodies."),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	m.responseBodySize, err = meter.Int64Histogram("http.server.response.body.size",
+		metric.WithDescription("Size of HTTP server response bodies."),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	m.componentHealth, err = meter.Int64Gauge("grffr.component.health",
+		metric.WithDescription("Result of the latest component health check; 2 is UP, 1 is DEGRADED and 0 is DOWN."),
+	)
+	errs = errors.Join(errs, err)
+	m.panics, err = meter.Int64Counter("grffr.panics",
+		metric.WithDescription("Number of recovered panics in HTTP handlers and components."),
+	)
+	errs = errors.Join(errs, err)
+	m.componentRestarts, err = meter.Int64ObservableCounter("grffr.component.restarts",
+		metric.WithDescription("Number of times a component has been restarted."),
+	)
+	errs = errors.Join(errs, err)
+	m.componentState, err = meter.Int64ObservableGauge("grffr.component.state",
+		metric.WithDescription("Life-cycle state of a component; 1 for the current state, 0 otherwise."),
+	)
+	errs = errors.Join(errs, err)
+	if errs != nil {
+		return fmt.Errorf("creating instruments: %w", errs)
+	}
+
+	_, err = meter.RegisterCallback(a.observeComponents, m.componentRestarts, m.componentState)
+	if err != nil {
+		return fmt.Errorf("registering component metrics: %w", err)
+	}
+	a.metrics = &m
+
+	return nil
+}
+
+// observeComponents reports restart counts and life-cycle state of components.
+func (a *App) observeComponents(_ context.Context, o metric.Observer) error {
+	for name, status := range a.componentStatuses() {
+		component := attribute.String("component", name)
+		o.ObserveInt64(a.metrics.componentRestarts, int64(status.Restarts), metric.WithAttributes(component))
+		for state := range slices.Values(a.metrics.componentStateNames) {
+			var v int64
+			if state == status.State {
+				v = 1
+			}
+			o.ObserveInt64(a.metrics.componentState, v, metric.WithAttributes(
+				component,
+				attribute.String("state", string(state)),
+			))
+		}
+	}
+
+	return nil
+}
+
+// recordHealth records the result of component health checks.
+func (a *App) recordHealth(ctx context.Context, results map[string]Health) {
+	if a.metrics == nil {
+		return
+	}
+
+	for name, health := range results {
+		var v int64
+		switch worstHealthStatus(health.Status) {
+		case HealthStatusUp:
+			v = 2
+		case HealthStatusDegraded:
+			v = 1
+		}
+		a.metrics.componentHealth.Record(ctx, v, metric.WithAttributes(attribute.String("component", name)))
+	}
+}
+
+// recordPanic counts a recovered panic from source.
+func (a *App) recordPanic(ctx context.Context, source string) {
+	if a.metrics == nil {
+		return
+	}
+
+	a.metrics.panics.Add(ctx, 1, metric.WithAttributes(attribute.String("source", source)))
+}
+
+// measureRequests is a middleware recording duration and sizes of requests
+// per route.
+func (a *App) measureRequests(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if a.metrics == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		attrs := metric.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.HTTPRoute(routePattern(r)),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		ctx := r.Context()
+		a.metrics.requestDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+		a.metrics.requestBodySize.Record(ctx, max(r.ContentLength, 0), attrs)
+		a.metrics.responseBodySize.Record(ctx, int64(ww.BytesWritten()), attrs)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// shutdownMetrics shuts down the meter provider, if configured
+// by the application.
+func (a *App) shutdownMetrics(ctx context.Context) error {
+	if a.meterShutdown == nil {
+		return nil
+	}
+
+	return a.meterShutdown(ctx)
+}
@@ -0,0 +1,71 @@
+package grffr
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// preferredMediaType returns the offer best matching the Accept header
+// of the request.
+//
+// The first offer is the default, returned when there is no Accept header
+// or no offers are acceptable. Ties are settled by order of offers.
+func preferredMediaType(r *http.Request, offers ...string) string {
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 {
+		return offers[0]
+	}
+
+	best, bestQ := offers[0], 0.0
+	for offer := range slices.Values(offers) {
+		q := acceptQuality(accept, offer)
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// acceptQuality returns the quality factor of mediaType in the Accept
+// header values, or zero if not acceptable.
+//
+// The most specific matching media range decides the quality.
+func acceptQuality(accept []string, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	quality, specificity := 0.0, -1
+	for value := range slices.Values(accept) {
+		for part := range strings.SplitSeq(value, ",") {
+			mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			s := -1
+			switch mediaRange {
+			case mediaType:
+				s = 2
+			case typ + "/*":
+				s = 1
+			case "*/*":
+				s = 0
+			}
+			if s <= specificity {
+				continue
+			}
+
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+			quality, specificity = q, s
+		}
+	}
+
+	return quality
+}
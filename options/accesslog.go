@@ -0,0 +1,43 @@
+package options
+
+import "log/slog"
+
+// AccessLog configures logging of requests to the HTTP server.
+//
+// The zero value logs all requests, except to health and version
+// end-points, at the default levels.
+type AccessLog struct {
+	// Disabled stops logging requests, see also WithoutAccessLog.
+	Disabled bool
+
+	// SampleRate is the fraction of requests logged, between 0 and 1,
+	// where 0, the default, logs all requests like 1 does.
+	// Requests failing with a server error are always logged.
+	SampleRate float64
+
+	// IncludeHealth logs requests to health and version end-points.
+	IncludeHealth bool
+
+	// ExcludedPaths are request paths not logged.
+	ExcludedPaths []string
+
+	// Level returns the level to log a request with the given status at.
+	//
+	// Default is Error for server errors, Warn for client errors and
+	// Info otherwise.
+	Level func(status int) slog.Level
+}
+
+// WithAccessLog configures logging of requests to the HTTP server.
+//
+// Fields left as zero values keep their defaults.
+func WithAccessLog(accessLog AccessLog) Option {
+	return func(cfg *Configuration) {
+		cfg.AccessLog = accessLog
+	}
+}
+
+// WithoutAccessLog disables logging of requests to the HTTP server.
+func WithoutAccessLog(cfg *Configuration) {
+	cfg.AccessLog.Disabled = true
+}
@@ -0,0 +1,13 @@
+package options
+
+// WithAdminAddr enables a separate admin HTTP server listening on addr,
+// in the form "host:port", e.g. "127.0.0.1:9090".
+//
+// The admin server hosts the health end-points and management end-points,
+// keeping them off the public HTTP server. It stays up while the public
+// HTTP server drains and shuts down, so probes keep answering.
+func WithAdminAddr(addr string) Option {
+	return func(cfg *Configuration) {
+		cfg.AdminAddr = addr
+	}
+}
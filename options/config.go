@@ -0,0 +1,54 @@
+package options
+
+// ConfigFile is a configuration file loaded into the application's own
+// configuration struct.
+type ConfigFile struct {
+	Path string
+
+	// Optional files are skipped if they do not exist.
+	Optional bool
+}
+
+// WithConfig populates the application's own configuration struct pointed
+// to by dst from configuration files and environment variables during
+// initialisation.
+//
+// Values are applied in the following order, where later sources override
+// earlier ones: default tags, configuration files in the order given,
+// environment variables.
+//
+// All missing and invalid values are reported at once, failing start up.
+// See package config for how fields are tagged.
+//
+// Configuration is reloaded on SIGHUP into a new copy of the struct, leaving
+// dst unchanged. Components receive reloaded configuration by implementing
+// grffr.Reloadable.
+func WithConfig(dst any) Option {
+	return func(cfg *Configuration) {
+		cfg.Config = dst
+	}
+}
+
+// WithConfigFile loads YAML (.yaml, .yml), TOML (.toml) or JSON (.json)
+// configuration files into the struct given to WithConfig.
+//
+// Files are applied in the order given, across calls, so a base file can be
+// followed by an environment specific overlay. Environment variables
+// override values from all files.
+func WithConfigFile(paths ...string) Option {
+	return func(cfg *Configuration) {
+		for _, path := range paths {
+			cfg.ConfigFiles = append(cfg.ConfigFiles, ConfigFile{Path: path})
+		}
+	}
+}
+
+// WithOptionalConfigFile loads configuration files like WithConfigFile,
+// but skips files that do not exist.
+func WithOptionalConfigFile(paths ...string) Option {
+	return func(cfg *Configuration) {
+		for _, path := range paths {
+			cfg.ConfigFiles = append(cfg.ConfigFiles, ConfigFile{Path: path, Optional: true})
+		}
+	}
+}
@@ -3,20 +3,34 @@ package options
 import (
 	"log/slog"
 	"net/http"
-)
+	"time"
 
-// TODO: The health checks should also come with configuration for path
-// so that can easilly be overriden with an Option.
-//
-// Right now an overriden health check will be mounted on `/.well-known/xx`
-// which might not fit into an organisation's standards.
+	"github.com/go-chi/chi/v5"
+	"go.cph.dev/grffr/logging"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
 
 // Option can be applied to Configuration when calling New to change defaults.
+//
+// Configuration is resolved in the following order, where later steps
+// override earlier ones: defaults, environment variables, options.
 type Option func(*Configuration)
 
 type Configuration struct {
+	// Debug enables debug logging, overriding LogLevel.
 	Debug bool
 
+	// Config points to the application's own configuration struct,
+	// populated from configuration files and environment variables
+	// during initialisation.
+	//
+	// See package config for how fields are tagged.
+	Config any
+
+	// ConfigFiles are the configuration files loaded into Config, in order.
+	ConfigFiles []ConfigFile
+
 	// Banner is a boolean flag that indicates whether the application should
 	// display a banner on startup.
 	Banner bool
@@ -24,6 +38,113 @@ type Configuration struct {
 	// Logger to use in application.
 	Logger *slog.Logger
 
+	// LogLevel is the minimum level logged by the default logger,
+	// taking precedence over LOG_LEVEL.
+	//
+	// Default is LOG_LEVEL if set, otherwise debug in development and
+	// info otherwise.
+	LogLevel *slog.Level
+
+	// LogTraceAttributes names the attributes the active span is logged with.
+	//
+	// Default is [logging.DefaultTraceAttributes].
+	LogTraceAttributes *logging.TraceAttributes
+
+	// TracerProvider to use in application.
+	//
+	// Default is to configure one from OTEL_* environment variables.
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider to use in application.
+	//
+	// Default is to expose metrics in the Prometheus text format at
+	// /metrics on the admin server.
+	MeterProvider metric.MeterProvider
+
+	// TraceHealth enables tracing of requests to health and version end-points.
+	TraceHealth bool
+
+	// TracingExcludedPaths are request paths not traced.
+	TracingExcludedPaths []string
+
+	// ServiceName is the name of the application.
+	//
+	// Default is read from the build information of the binary.
+	ServiceName string
+
+	// ServiceVersion is the version of the application.
+	//
+	// Default is read from the build information of the binary.
+	ServiceVersion string
+
+	// HTTPAddr is the address the HTTP server listens on.
+	//
+	// Default is :8080, overridden by HTTP_ADDR or HTTP_PORT.
+	HTTPAddr string
+
+	// HTTPTimeouts of the HTTP server.
+	//
+	// Overridden by HTTP_READ_TIMEOUT, HTTP_READ_HEADER_TIMEOUT,
+	// HTTP_WRITE_TIMEOUT and HTTP_IDLE_TIMEOUT.
+	HTTPTimeouts HTTPTimeouts
+
+	// HTTPMaxHeaderBytes is the maximum size of request headers.
+	//
+	// Default is [net/http.DefaultMaxHeaderBytes], overridden by
+	// HTTP_MAX_HEADER_BYTES.
+	HTTPMaxHeaderBytes int
+
+	// AdminAddr is the address of the admin HTTP server hosting health
+	// and management end-points.
+	//
+	// Default is empty, which disables the admin server and serves
+	// health end-points on the HTTP server. Overridden by ADMIN_ADDR.
+	AdminAddr string
+
+	// AccessLog configures logging of requests to the HTTP server.
+	//
+	// Default is to log all requests, except to health end-points.
+	AccessLog AccessLog
+
+	// RequestIDHeader is the header request IDs are read from and echoed in.
+	//
+	// Default is X-Request-ID.
+	RequestIDHeader string
+
+	// NotFoundHandler handles requests not matching any route.
+	//
+	// Default is to respond with a 404 problem.
+	NotFoundHandler http.Handler
+
+	// MethodNotAllowedHandler handles requests matching a route, but
+	// not its method.
+	//
+	// Default is to respond with a 405 problem.
+	MethodNotAllowedHandler http.Handler
+
+	// Routes registers application routes on the application's router.
+	Routes []func(chi.Router)
+
+	// StartupTimeout is how long to wait for components to become ready
+	// before the application gives up and shuts down.
+	//
+	// Zero or less waits indefinitely.
+	StartupTimeout time.Duration
+
+	// Supervision is the default policy applied when a component fails.
+	Supervision Supervision
+
+	// DrainDelay is how long to wait after readiness is flipped to
+	// unavailable before shutting down the HTTP server.
+	DrainDelay time.Duration
+
+	// DrainPolls ends the drain phase early once the readiness end-point
+	// has been polled this many times. Zero or less always waits DrainDelay.
+	DrainPolls int
+
+	// ShutdownTimeouts are how long each phase of shutting down may take.
+	ShutdownTimeouts ShutdownTimeouts
+
 	// Purpose: To indicate whether the container
 	// is running. If the liveness probe fails, the
 	// container will be restarted.
@@ -52,4 +173,41 @@ type Configuration struct {
 	//
 	// Default location is /.well-known/health/status
 	StatusHandler http.Handler
+
+	// HealthPrefix is prepended to the paths of all health end-points.
+	//
+	// Default is /.well-known/health
+	HealthPrefix string
+
+	// LivenessPaths the LivenessHandler is mounted on below HealthPrefix.
+	//
+	// Default is /alive
+	LivenessPaths []string
+
+	// ReadinessPaths the ReadinessHandler is mounted on below HealthPrefix.
+	//
+	// Default is /ready
+	ReadinessPaths []string
+
+	// StartupPaths the StartupHandler is mounted on below HealthPrefix.
+	//
+	// Default is /startup
+	StartupPaths []string
+
+	// StatusPaths the StatusHandler is mounted on below HealthPrefix.
+	//
+	// Default is /status
+	StatusPaths []string
+
+	// VersionHandler reports the name, version and build of the application.
+	VersionHandler http.Handler
+
+	// VersionPaths the VersionHandler is mounted on.
+	//
+	// Default is /.well-known/version
+	VersionPaths []string
+
+	// HealthCheckTimeout is the time each component health check is given
+	// to report before it is considered down.
+	HealthCheckTimeout time.Duration
 }
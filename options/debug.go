@@ -1,6 +1,7 @@
 package options
 
-// WithDebug enables debug logging in the framework.
+// WithDebug enables debug logging in the framework, overriding the log
+// level set by WithLogLevel or LOG_LEVEL.
 func WithDebug(cfg *Configuration) {
 	cfg.Debug = true
 }
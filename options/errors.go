@@ -0,0 +1,22 @@
+package options
+
+import "net/http"
+
+// WithNotFoundHandler sets the handler for requests not matching any route.
+//
+// Default is to respond with a 404 problem.
+func WithNotFoundHandler(h http.Handler) Option {
+	return func(cfg *Configuration) {
+		cfg.NotFoundHandler = h
+	}
+}
+
+// WithMethodNotAllowedHandler sets the handler for requests matching a route,
+// but not its method.
+//
+// Default is to respond with a 405 problem.
+func WithMethodNotAllowedHandler(h http.Handler) Option {
+	return func(cfg *Configuration) {
+		cfg.MethodNotAllowedHandler = h
+	}
+}
@@ -1,6 +1,9 @@
 package options
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // DisableDefaultHealthHandlers prevents the following default
 // handlers to be registered during initialisation:
@@ -19,19 +22,19 @@ func DisableDefaultHealthHandlers(cfg *Configuration) {
 // WithHealthStartupCheck overrides the default start up check with
 // the given handler.
 //
-// The end-point is still served on the default location of
+// The end-point is served on the configured health paths, by default
 //
 //	GET /.well-known/health/startup
 func WithHealthStartupCheck(h http.Handler) Option {
 	return func(cfg *Configuration) {
-		cfg.LivenessHandler = h
+		cfg.StartupHandler = h
 	}
 }
 
 // WithHealthLivenessCheck overrides the default liveness health check with
 // the given handler.
 //
-// The end-point is still served on the default location of
+// The end-point is served on the configured health paths, by default
 //
 //	GET /.well-known/health/alive
 func WithHealthLivenessCheck(h http.Handler) Option {
@@ -43,9 +46,9 @@ func WithHealthLivenessCheck(h http.Handler) Option {
 // WithHealthReadinessCheck overrides the default readiness health check with
 // the given handler.
 //
-// The end-point is still served on the default location of
+// The end-point is served on the configured health paths, by default
 //
-//	GET /.well-known/health/readinessHandler
+//	GET /.well-known/health/ready
 func WithHealthReadinessCheck(h http.Handler) Option {
 	return func(cfg *Configuration) {
 		cfg.ReadinessHandler = h
@@ -55,7 +58,7 @@ func WithHealthReadinessCheck(h http.Handler) Option {
 // WithHealthStatusCheck overrides the default status health check with
 // the given handler.
 //
-// The end-point is still served on the default location of
+// The end-point is served on the configured health paths, by default
 //
 //	GET /.well-known/health/status
 func WithHealthStatusCheck(h http.Handler) Option {
@@ -63,3 +66,71 @@ func WithHealthStatusCheck(h http.Handler) Option {
 		cfg.StatusHandler = h
 	}
 }
+
+// WithHealthCheckTimeout sets the time each component health check is
+// given to report before it is considered down.
+//
+// A timeout of zero or less waits for health checks indefinitely.
+func WithHealthCheckTimeout(d time.Duration) Option {
+	return func(cfg *Configuration) {
+		cfg.HealthCheckTimeout = d
+	}
+}
+
+// WithHealthPrefix sets the path prefix of all health end-points.
+//
+// Use an empty prefix to mount health end-points at the root, e.g.
+// Kubernetes style:
+//
+//	options.WithHealthPrefix("")
+//	options.WithHealthLivenessPaths("/livez")
+//	options.WithHealthReadinessPaths("/readyz")
+func WithHealthPrefix(prefix string) Option {
+	return func(cfg *Configuration) {
+		cfg.HealthPrefix = prefix
+	}
+}
+
+// WithHealthStartupPaths sets the paths below the health prefix
+// the start up check is served on.
+//
+// Multiple paths serve the same check, e.g. while migrating from
+// one path to another.
+func WithHealthStartupPaths(paths ...string) Option {
+	return func(cfg *Configuration) {
+		cfg.StartupPaths = paths
+	}
+}
+
+// WithHealthLivenessPaths sets the paths below the health prefix
+// the liveness check is served on.
+//
+// Multiple paths serve the same check, e.g. while migrating from
+// one path to another.
+func WithHealthLivenessPaths(paths ...string) Option {
+	return func(cfg *Configuration) {
+		cfg.LivenessPaths = paths
+	}
+}
+
+// WithHealthReadinessPaths sets the paths below the health prefix
+// the readiness check is served on.
+//
+// Multiple paths serve the same check, e.g. while migrating from
+// one path to another.
+func WithHealthReadinessPaths(paths ...string) Option {
+	return func(cfg *Configuration) {
+		cfg.ReadinessPaths = paths
+	}
+}
+
+// WithHealthStatusPaths sets the paths below the health prefix
+// the status check is served on.
+//
+// Multiple paths serve the same check, e.g. while migrating from
+// one path to another.
+func WithHealthStatusPaths(paths ...string) Option {
+	return func(cfg *Configuration) {
+		cfg.StatusPaths = paths
+	}
+}
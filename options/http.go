@@ -0,0 +1,40 @@
+package options
+
+import "time"
+
+// HTTPTimeouts for the HTTP server.
+//
+// See [net/http.Server] for the meaning of each timeout.
+// Zero means no timeout.
+type HTTPTimeouts struct {
+	Read       time.Duration
+	ReadHeader time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+}
+
+// WithHTTPAddr sets the address the HTTP server listens on, in the
+// form "host:port", e.g. "127.0.0.1:8080" or ":8080" for all interfaces.
+func WithHTTPAddr(addr string) Option {
+	return func(cfg *Configuration) {
+		cfg.HTTPAddr = addr
+	}
+}
+
+// WithHTTPTimeouts sets the timeouts of the HTTP server.
+//
+// Long-polling, streaming or upload end-points might need longer
+// timeouts than the defaults.
+func WithHTTPTimeouts(t HTTPTimeouts) Option {
+	return func(cfg *Configuration) {
+		cfg.HTTPTimeouts = t
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of request headers
+// accepted by the HTTP server.
+func WithMaxHeaderBytes(n int) Option {
+	return func(cfg *Configuration) {
+		cfg.HTTPMaxHeaderBytes = n
+	}
+}
@@ -1,6 +1,10 @@
 package options
 
-import "log/slog"
+import (
+	"log/slog"
+
+	"go.cph.dev/grffr/logging"
+)
 
 // WithLogger sets the logger to use within the framework.
 func WithLogger(logger *slog.Logger) Option {
@@ -8,3 +12,22 @@ func WithLogger(logger *slog.Logger) Option {
 		cfg.Logger = logger
 	}
 }
+
+// WithLogTraceAttributes sets the names of the attributes the active span
+// is logged with, e.g. [logging.DatadogTraceAttributes].
+//
+// Only used with the default logger.
+func WithLogTraceAttributes(attrs logging.TraceAttributes) Option {
+	return func(cfg *Configuration) {
+		cfg.LogTraceAttributes = &attrs
+	}
+}
+
+// WithLogLevel sets the minimum level logged by the default logger.
+//
+// The level can be changed at runtime through the admin server.
+func WithLogLevel(level slog.Level) Option {
+	return func(cfg *Configuration) {
+		cfg.LogLevel = &level
+	}
+}
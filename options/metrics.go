@@ -0,0 +1,14 @@
+package options
+
+import "go.opentelemetry.io/otel/metric"
+
+// WithMeterProvider sets the meter provider to use within the framework,
+// instead of exposing metrics in the Prometheus text format on
+// the admin server.
+//
+// The application does not shut down a given meter provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(cfg *Configuration) {
+		cfg.MeterProvider = mp
+	}
+}
@@ -0,0 +1,9 @@
+package options
+
+// WithRequestIDHeader sets the header request IDs are read from and
+// echoed in. Default is X-Request-ID, also used if header is empty.
+func WithRequestIDHeader(header string) Option {
+	return func(cfg *Configuration) {
+		cfg.RequestIDHeader = header
+	}
+}
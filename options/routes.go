@@ -0,0 +1,13 @@
+package options
+
+import "github.com/go-chi/chi/v5"
+
+// WithRoutes registers application routes on the application's router
+// during initialisation.
+//
+// The function can be given multiple times, and is called in the order given.
+func WithRoutes(fn func(chi.Router)) Option {
+	return func(cfg *Configuration) {
+		cfg.Routes = append(cfg.Routes, fn)
+	}
+}
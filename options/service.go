@@ -0,0 +1,21 @@
+package options
+
+// WithServiceInfo sets the name and version of the application.
+//
+// Without it, the name and version are read from the build information
+// embedded in the binary by the Go toolchain.
+func WithServiceInfo(name, version string) Option {
+	return func(cfg *Configuration) {
+		cfg.ServiceName = name
+		cfg.ServiceVersion = version
+	}
+}
+
+// WithVersionPaths sets the paths the version end-point is served on.
+//
+// No paths disables the version end-point.
+func WithVersionPaths(paths ...string) Option {
+	return func(cfg *Configuration) {
+		cfg.VersionPaths = paths
+	}
+}
@@ -0,0 +1,61 @@
+package options
+
+import "time"
+
+// WithReadinessDrain sets how long to wait after readiness has been flipped
+// to unavailable before the HTTP server is shut down.
+//
+// The delay gives proxies/load balancers a chance to observe the failing
+// readiness check and route traffic elsewhere. A delay of zero or less
+// disables the drain phase.
+func WithReadinessDrain(delay time.Duration) Option {
+	return func(cfg *Configuration) {
+		cfg.DrainDelay = delay
+	}
+}
+
+// WithReadinessDrainPolls ends the drain phase early once the readiness
+// end-point has been polled n times after being flipped to unavailable.
+//
+// The drain phase never lasts longer than the delay set
+// with WithReadinessDrain.
+func WithReadinessDrainPolls(n int) Option {
+	return func(cfg *Configuration) {
+		cfg.DrainPolls = n
+	}
+}
+
+// ShutdownTimeouts are how long each phase of shutting down may take,
+// after the drain phase. Each phase gets its own timeout, so a slow phase
+// does not leave later phases without time. Zero means no timeout.
+type ShutdownTimeouts struct {
+	// HTTP is how long to wait for in-flight requests to the HTTP
+	// servers to complete.
+	HTTP time.Duration
+
+	// Components is how long to wait for components to stop.
+	Components time.Duration
+
+	// Telemetry is how long to wait for traces and metrics to be flushed.
+	Telemetry time.Duration
+}
+
+// WithShutdownTimeout sets how long each phase of shutting down may take,
+// after the drain phase.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(cfg *Configuration) {
+		cfg.ShutdownTimeouts = ShutdownTimeouts{
+			HTTP:       d,
+			Components: d,
+			Telemetry:  d,
+		}
+	}
+}
+
+// WithShutdownTimeouts sets how long each phase of shutting down may take,
+// after the drain phase.
+func WithShutdownTimeouts(t ShutdownTimeouts) Option {
+	return func(cfg *Configuration) {
+		cfg.ShutdownTimeouts = t
+	}
+}
@@ -0,0 +1,13 @@
+package options
+
+import "time"
+
+// WithStartupTimeout sets how long the application waits for components
+// to become ready before giving up and shutting down.
+//
+// A timeout of zero or less waits indefinitely.
+func WithStartupTimeout(d time.Duration) Option {
+	return func(cfg *Configuration) {
+		cfg.StartupTimeout = d
+	}
+}
@@ -0,0 +1,63 @@
+package options
+
+import "time"
+
+// SupervisionStrategy decides what happens when a component fails,
+// that is when its Start returns an error or panics.
+type SupervisionStrategy int
+
+const (
+	// SupervisionIgnore logs the failure and keeps the application running.
+	SupervisionIgnore SupervisionStrategy = iota
+
+	// SupervisionRestart starts the component again after a backoff.
+	SupervisionRestart
+
+	// SupervisionEscalate shuts down the application, which then exits
+	// with a non-zero exit code.
+	SupervisionEscalate
+)
+
+// Supervision policy for components.
+type Supervision struct {
+	// Strategy to apply when a component fails.
+	Strategy SupervisionStrategy
+
+	// MaxRestarts is the number of restarts before giving up on
+	// the component. Zero or less restarts indefinitely.
+	//
+	// Only used with SupervisionRestart.
+	MaxRestarts int
+
+	// InitialBackoff is the time to wait before the first restart.
+	// The backoff is doubled for every following restart.
+	//
+	// Only used with SupervisionRestart.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the time to wait between restarts.
+	//
+	// Only used with SupervisionRestart.
+	MaxBackoff time.Duration
+
+	// EscalateOnGiveUp shuts down the application when giving up
+	// restarting a component after MaxRestarts.
+	//
+	// Only used with SupervisionRestart.
+	EscalateOnGiveUp bool
+
+	// IgnoreInReadiness keeps the state of the component out of the
+	// readiness probe. A failed component still fails the status
+	// end-point.
+	IgnoreInReadiness bool
+}
+
+// WithSupervision sets the default supervision policy for components.
+//
+// Components can override the default by implementing the
+// Supervised interface.
+func WithSupervision(s Supervision) Option {
+	return func(cfg *Configuration) {
+		cfg.Supervision = s
+	}
+}
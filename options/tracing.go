@@ -0,0 +1,27 @@
+package options
+
+import "go.opentelemetry.io/otel/trace"
+
+// WithTracerProvider sets the tracer provider to use within the framework,
+// instead of configuring one from OTEL_* environment variables.
+//
+// The application does not shut down a given tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(cfg *Configuration) {
+		cfg.TracerProvider = tp
+	}
+}
+
+// WithHealthTracing enables tracing of requests to the health and
+// version end-points, which are not traced by default.
+func WithHealthTracing(cfg *Configuration) {
+	cfg.TraceHealth = true
+}
+
+// WithTracingExcludedPaths excludes requests to the given paths
+// from being traced.
+func WithTracingExcludedPaths(paths ...string) Option {
+	return func(cfg *Configuration) {
+		cfg.TracingExcludedPaths = append(cfg.TracingExcludedPaths, paths...)
+	}
+}
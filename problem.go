@@ -0,0 +1,186 @@
+package grffr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.cph.dev/grffr/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MediaTypeProblemJSON is the media type of problem details.
+const MediaTypeProblemJSON = "application/problem+json"
+
+// Problem details for HTTP APIs, as described by RFC 9457.
+//
+// Problem is also an error, which WriteProblem writes as is.
+type Problem struct {
+	Type      string            `json:"type,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Status    int               `json:"status,omitempty"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	TraceID   string            `json:"trace_id,omitempty"`
+	Errors    []ValidationError `json:"errors,omitempty"`
+}
+
+// NewProblem returns a problem with the given status and detail.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+
+	return p.Title + ": " + p.Detail
+}
+
+// HTTPStatus returns the status of the problem.
+func (p *Problem) HTTPStatus() int {
+	return p.Status
+}
+
+// StatusError is an error mapping to an HTTP status code.
+type StatusError interface {
+	error
+	HTTPStatus() int
+}
+
+type statusError int
+
+func (e statusError) Error() string {
+	return strings.ToLower(http.StatusText(int(e)))
+}
+
+func (e statusError) HTTPStatus() int {
+	return int(e)
+}
+
+// Errors mapping to HTTP status codes.
+//
+// Wrap them to give details, e.g.
+//
+//	fmt.Errorf("user %s: %w", id, grffr.ErrNotFound)
+var (
+	ErrBadRequest       error = statusError(http.StatusBadRequest)
+	ErrUnauthorized     error = statusError(http.StatusUnauthorized)
+	ErrForbidden        error = statusError(http.StatusForbidden)
+	ErrNotFound         error = statusError(http.StatusNotFound)
+	ErrConflict         error = statusError(http.StatusConflict)
+	ErrGone             error = statusError(http.StatusGone)
+	ErrUnprocessable    error = statusError(http.StatusUnprocessableEntity)
+	ErrTooManyRequests  error = statusError(http.StatusTooManyRequests)
+	ErrUnavailable      error = statusError(http.StatusServiceUnavailable)
+	ErrMethodNotAllowed error = statusError(http.StatusMethodNotAllowed)
+)
+
+// ValidationError describes an invalid field of a request.
+type ValidationError struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
+}
+
+// ValidationErrors is a list of invalid fields of a request.
+//
+// It is written as a 422 problem listing the errors.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, v := range e {
+		msgs = append(msgs, v.Field+": "+v.Detail)
+	}
+
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+func (e ValidationErrors) HTTPStatus() int {
+	return http.StatusUnprocessableEntity
+}
+
+// WriteProblem writes err to w as problem details.
+//
+// The status is taken from a [StatusError] in the error chain, otherwise
+// it is 500. The error message is given as detail for client errors only,
+// so internals are not leaked; server errors are logged instead. A
+// [Problem] in the error chain is written as is, and not logged. The request and trace
+// IDs of the request are always included.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+
+	var (
+		problem Problem
+		p       *Problem
+		se      StatusError
+		ve      ValidationErrors
+	)
+	if errors.As(err, &p) {
+		problem = *p
+	} else {
+		status := http.StatusInternalServerError
+		if errors.As(err, &se) && validStatus(se.HTTPStatus()) {
+			status = se.HTTPStatus()
+		}
+		problem = *NewProblem(status, "")
+		if status < http.StatusInternalServerError {
+			problem.Detail = err.Error()
+		} else {
+			slog.ErrorContext(ctx, "Request failed", logging.Error(err))
+		}
+		if errors.As(err, &ve) {
+			problem.Errors = ve
+		}
+	}
+
+	if !validStatus(problem.Status) {
+		problem.Status = http.StatusInternalServerError
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+	problem.RequestID = RequestID(ctx)
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		problem.TraceID = sc.TraceID().String()
+	}
+
+	w.Header().Set("Content-Type", MediaTypeProblemJSON)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// validStatus reports whether status can be written as an HTTP status code.
+func validStatus(status int) bool {
+	return status >= 100 && status <= 599
+}
+
+// notFoundHandler writes a 404 problem.
+func notFoundHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, r, fmt.Errorf("no route for %s: %w", r.URL.Path, ErrNotFound))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// methodNotAllowedHandler writes a 405 problem.
+func methodNotAllowedHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, r, fmt.Errorf("method %s on %s: %w", r.Method, r.URL.Path, ErrMethodNotAllowed))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,64 @@
+package grffr
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.cph.dev/grffr/logging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverRequests is a middleware recovering panics in handlers.
+//
+// The panic is logged with its stack and the request context, recorded
+// on the active span and counted, and the client gets a 500 problem
+// response. [http.ErrAbortHandler] is re-panicked to abort the response
+// as intended. If the handler had already started the response, it is
+// aborted as well, rather than corrupted by writing a problem after it.
+func (a *App) recoverRequests(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			ctx := r.Context()
+			err := &PanicError{Value: v, Stack: debug.Stack()}
+			slog.ErrorContext(ctx, "Panic serving HTTP request", logging.Panic(err.Value, err.Stack))
+			trace.SpanFromContext(ctx).RecordError(err)
+			a.recordPanic(ctx, "http")
+
+			if ww.Status() != 0 || ww.BytesWritten() > 0 {
+				panic(http.ErrAbortHandler)
+			}
+			WriteProblem(ww, r, NewProblem(http.StatusInternalServerError, ""))
+		}()
+
+		next.ServeHTTP(ww, r)
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,181 @@
+package grffr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+
+	"go.cph.dev/grffr/config"
+	"go.cph.dev/grffr/logging"
+)
+
+// Reloadable is a component accepting configuration reloads.
+//
+// Reload is called with a pointer to a new copy of the struct given to
+// [options.WithConfig] when configuration is reloaded, on SIGHUP or through
+// the admin server. Components are reloaded in dependency order. If Reload
+// returns an error, the new configuration is rejected and components
+// already reloaded are reloaded again with the previous configuration, in
+// reverse order.
+//
+// Reload must not keep using the previous configuration once it returns
+// without error.
+type Reloadable interface {
+	Reload(ctx context.Context, cfg any) error
+}
+
+// Config returns the current configuration, a pointer to the struct given
+// to [options.WithConfig] or to a copy of it loaded by the latest reload.
+//
+// The struct given to WithConfig is never changed by reloads, so it can
+// be read without synchronisation.
+func (a *App) Config() any {
+	a.configMu.RLock()
+	defer a.configMu.RUnlock()
+
+	return a.config
+}
+
+// loadConfig populates dst from the configured files and environment,
+// recording the source of each value in sources.
+func (a *App) loadConfig(dst any, sources config.Sources) error {
+	opts := []config.Option{config.WithSources(sources)}
+	for _, f := range a.configuration.ConfigFiles {
+		if f.Optional {
+			opts = append(opts, config.WithOptionalFiles(f.Path))
+		} else {
+			opts = append(opts, config.WithFiles(f.Path))
+		}
+	}
+
+	return config.Load(dst, opts...)
+}
+
+// reload re-reads configuration and passes it to Reloadable components,
+// and re-reads the log level from LOG_LEVEL.
+//
+// The configuration is loaded into a new struct, so the current one is
+// kept if loading, validation or any component fails.
+func (a *App) reload(ctx context.Context) error {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	if !a.isStarted.Load() || a.isShuttingDown.Load() {
+		return fmt.Errorf("application not running: %w", ErrUnavailable)
+	}
+
+	start := time.Now()
+	slog.InfoContext(ctx, "Reloading configuration.")
+
+	env, err := loadEnvironment()
+	if err != nil {
+		return fmt.Errorf("loading environment: %w", err)
+	}
+
+	var reloaded int
+	if current := a.Config(); current != nil {
+		reloaded, err = a.reloadConfig(ctx, current)
+		if err != nil {
+			return err
+		}
+	}
+	a.logLevel.configure(a.configuredLogLevel(env))
+
+	slog.InfoContext(ctx, "Configuration reloaded.",
+		slog.Int("components", reloaded),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return nil
+}
+
+// reloadConfig loads the application's own configuration into a new
+// struct of the same type as current, and passes it to Reloadable
+// components. It returns the number of components reloaded.
+func (a *App) reloadConfig(ctx context.Context, current any) (int, error) {
+	next := reflect.New(reflect.TypeOf(current).Elem()).Interface()
+	sources := config.Sources{}
+	if err := a.loadConfig(next, sources); err != nil {
+		return 0, fmt.Errorf("loading configuration: %w", err)
+	}
+
+	var reloaded []Reloadable
+	for _, c := range a.components {
+		r, ok := c.(Reloadable)
+		if !ok {
+			continue
+		}
+		if err := r.Reload(componentContext(ctx, c), next); err != nil {
+			a.rollback(ctx, reloaded, current)
+			return 0, fmt.Errorf("component %s rejected configuration: %w", componentName(c), err)
+		}
+		reloaded = append(reloaded, r)
+	}
+
+	a.configMu.Lock()
+	a.config = next
+	a.configSources = sources
+	a.configMu.Unlock()
+
+	return len(reloaded), nil
+}
+
+// rollback reloads components with the previous configuration, in
+// reverse order.
+func (a *App) rollback(ctx context.Context, reloaded []Reloadable, previous any) {
+	for _, r := range slices.Backward(reloaded) {
+		c := r.(Component)
+		if err := r.Reload(componentContext(ctx, c), previous); err != nil {
+			slog.ErrorContext(ctx, "Rolling back configuration of component.",
+				slog.String("component", componentName(c)),
+				logging.Error(err),
+			)
+		}
+	}
+}
+
+// reloadOnHangup reloads configuration on SIGHUP until ctx is done.
+func (a *App) reloadOnHangup(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			if err := a.reload(ctx); err != nil {
+				slog.ErrorContext(ctx, "Reloading configuration.", logging.Error(err))
+			}
+		}
+	}
+}
+
+// reloadHandler reloads configuration, answering 204 No Content on
+// success and a problem otherwise.
+func (a *App) reloadHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		err := a.reload(r.Context())
+		if err != nil {
+			if !errors.Is(err, ErrUnavailable) {
+				slog.ErrorContext(r.Context(), "Reloading configuration.", logging.Error(err))
+				// The error may name configuration values; keep it in the logs
+				err = NewProblem(http.StatusUnprocessableEntity, "Configuration rejected, see the logs for details.")
+			}
+			WriteProblem(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,74 @@
+package grffr
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.cph.dev/grffr/logging"
+)
+
+const (
+	// requestIDMaxLength is the maximum length of an incoming request ID.
+	requestIDMaxLength = 128
+
+	// requestIDHeader is the default header of request IDs.
+	requestIDHeader = "X-Request-ID"
+)
+
+type requestIDKey struct{}
+
+// RequestID returns the ID of the request being served with ctx,
+// or an empty string if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID reports whether an incoming request ID is accepted.
+//
+// IDs must be at most requestIDMaxLength characters of letters, digits
+// and the punctuation -_.:+/=@
+func validRequestID(id string) bool {
+	if id == "" || len(id) > requestIDMaxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case strings.ContainsRune("-_.:+/=@", r):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// identifyRequests is a middleware giving every request an ID.
+//
+// A valid ID in the incoming request header is used, otherwise a UUIDv7
+// is generated. The ID is echoed in the response header, stored in the
+// request context (see [RequestID]) and added to log records.
+//
+// An empty header configured falls back to the default header.
+func (a *App) identifyRequests(next http.Handler) http.Handler {
+	header := a.configuration.RequestIDHeader
+	if header == "" {
+		header = requestIDHeader
+	}
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if !validRequestID(id) {
+			id = uuid.Must(uuid.NewV7()).String()
+		}
+
+		w.Header().Set(header, id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = logging.AppendCtx(ctx, slog.String("request_id", id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,257 @@
+package grffr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.cph.dev/grffr/logging"
+	"go.cph.dev/grffr/options"
+)
+
+const (
+	// restartInitialBackoff is used when a restart policy has no initial backoff.
+	restartInitialBackoff = time.Second
+
+	// restartMaxBackoff is used when a restart policy has no max backoff.
+	restartMaxBackoff = time.Minute
+)
+
+// Supervised is a component with its own supervision policy.
+//
+// The policy overrides the application default set
+// with [options.WithSupervision].
+type Supervised interface {
+	Supervision() options.Supervision
+}
+
+// ComponentState is the life-cycle state of a supervised component.
+type ComponentState string
+
+const (
+	ComponentStateStarting   ComponentState = "STARTING"
+	ComponentStateRunning    ComponentState = "RUNNING"
+	ComponentStateRestarting ComponentState = "RESTARTING"
+	ComponentStateFailed     ComponentState = "FAILED"
+	ComponentStateStopped    ComponentState = "STOPPED"
+)
+
+// ComponentStatus is a snapshot of a supervised component.
+type ComponentStatus struct {
+	State     ComponentState `json:"state"`
+	Restarts  int            `json:"restarts"`
+	LastError string         `json:"last_error,omitempty"`
+
+	// Health is the result of the component's health check, if it
+	// implements [Healthchecker].
+	Health *Health `json:"health,omitempty"`
+}
+
+// supervisor runs a component and applies its supervision policy
+// when it fails.
+type supervisor struct {
+	component Component
+	key       string
+	policy    options.Supervision
+	onPanic   func(context.Context)
+
+	// started is set once the component has been started, so only
+	// started components are stopped.
+	started atomic.Bool
+
+	// done is closed when run returns.
+	done chan struct{}
+
+	mu       sync.Mutex
+	state    ComponentState
+	restarts int
+	lastErr  error
+}
+
+func newSupervisor(c Component, policy options.Supervision) *supervisor {
+	if s, ok := c.(Supervised); ok {
+		policy = s.Supervision()
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = restartInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = restartMaxBackoff
+	}
+
+	return &supervisor{
+		component: c,
+		policy:    policy,
+		state:     ComponentStateStarting,
+		done:      make(chan struct{}),
+	}
+}
+
+// run starts the component and keeps supervising it until it either
+// returns without error, fails permanently or the application shuts down.
+//
+// ctx is done when the application shuts down, while startCtx is passed
+// on to the component. escalate is called if the failure should shut
+// down the application.
+func (s *supervisor) run(ctx, startCtx context.Context, escalate func(error)) {
+	defer close(s.done)
+
+	backoff := s.policy.InitialBackoff
+	for {
+		s.setState(ComponentStateRunning, nil)
+		err := s.start(startCtx)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			slog.DebugContext(startCtx, "Component stopped during shutdown", logging.Error(err))
+			return
+		}
+
+		switch s.policy.Strategy {
+		case options.SupervisionRestart:
+			if s.policy.MaxRestarts > 0 && s.Status().Restarts >= s.policy.MaxRestarts {
+				s.setState(ComponentStateFailed, err)
+				slog.ErrorContext(startCtx, "Giving up restarting component",
+					slog.Int("restarts", s.policy.MaxRestarts),
+					logging.Error(err),
+				)
+				if s.policy.EscalateOnGiveUp {
+					escalate(fmt.Errorf("component %s failed after %d restarts: %w", componentName(s.component), s.policy.MaxRestarts, err))
+				}
+				return
+			}
+
+			s.setState(ComponentStateRestarting, err)
+			slog.WarnContext(startCtx, "Component failed, restarting",
+				slog.Duration("backoff", backoff),
+				logging.Error(err),
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, s.policy.MaxBackoff)
+
+			s.mu.Lock()
+			s.restarts++
+			s.mu.Unlock()
+
+		case options.SupervisionEscalate:
+			s.setState(ComponentStateFailed, err)
+			slog.ErrorContext(startCtx, "Component failed, shutting down application", logging.Error(err))
+			escalate(fmt.Errorf("component %s failed: %w", componentName(s.component), err))
+			return
+
+		default:
+			s.setState(ComponentStateFailed, err)
+			slog.WarnContext(startCtx, "Starting component failed", logging.Error(err))
+			return
+		}
+	}
+}
+
+// start the component, recovering a panic as a [PanicError].
+func (s *supervisor) start(ctx context.Context) (err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		panicErr := &PanicError{Value: v, Stack: debug.Stack()}
+		slog.ErrorContext(ctx, "Panic in component", logging.Panic(panicErr.Value, panicErr.Stack))
+		if s.onPanic != nil {
+			s.onPanic(ctx)
+		}
+		err = panicErr
+	}()
+
+	return s.component.Start(ctx)
+}
+
+func (s *supervisor) setState(state ComponentState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	if err != nil {
+		s.lastErr = err
+	}
+}
+
+// failure returns the error the component failed with, if it has
+// failed permanently.
+func (s *supervisor) failure() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != ComponentStateFailed {
+		return nil
+	}
+
+	return s.lastErr
+}
+
+// Status returns a snapshot of the supervised component.
+func (s *supervisor) Status() ComponentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := ComponentStatus{
+		State:    s.state,
+		Restarts: s.restarts,
+	}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+
+	return status
+}
+
+// healthStatus maps the state of a component to a health status.
+//
+// A failed component is DOWN and a restarting one DEGRADED.
+func (s ComponentState) healthStatus() HealthStatus {
+	switch s {
+	case ComponentStateFailed:
+		return HealthStatusDown
+	case ComponentStateRestarting:
+		return HealthStatusDegraded
+	default:
+		return HealthStatusUp
+	}
+}
+
+// supervisionStatus rolls up the state of supervised components.
+//
+// With readiness set, components whose policy opts out of the
+// readiness probe are left out.
+func (a *App) supervisionStatus(readiness bool) HealthStatus {
+	a.supervisorsMu.Lock()
+	defer a.supervisorsMu.Unlock()
+	statuses := make([]HealthStatus, 0, len(a.supervisors))
+	for _, s := range a.supervisors {
+		if readiness && s.policy.IgnoreInReadiness {
+			continue
+		}
+		statuses = append(statuses, s.Status().State.healthStatus())
+	}
+
+	return worstHealthStatus(statuses...)
+}
+
+// componentStatuses returns the status of all supervised components
+// keyed by component key, see [componentKeys].
+func (a *App) componentStatuses() map[string]ComponentStatus {
+	a.supervisorsMu.Lock()
+	defer a.supervisorsMu.Unlock()
+	statuses := make(map[string]ComponentStatus, len(a.supervisors))
+	for _, s := range a.supervisors {
+		statuses[s.key] = s.Status()
+	}
+
+	return statuses
+}
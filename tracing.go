@@ -0,0 +1,231 @@
+package grffr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the name of the tracer used by the framework itself.
+const tracerName = "go.cph.dev/grffr"
+
+// initTracing configures the tracer provider and propagators.
+//
+// Unless a tracer provider is given with [options.WithTracerProvider],
+// one is configured from the standard OTEL_* environment variables:
+//
+//   - OTEL_SDK_DISABLED disables tracing if "true".
+//   - OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES describe the service,
+//     defaulting to the application's name and version.
+//   - OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG select the sampler.
+//   - OTEL_TRACES_EXPORTER selects the exporter; "otlp" (OTLP/HTTP configured
+//     by OTEL_EXPORTER_OTLP_*), "console" (stdout), "file" (written to
+//     OTEL_TRACES_FILE, default traces.jsonl) or "none". Default is "otlp"
+//     if an OTLP endpoint is configured, otherwise "none".
+func (a *App) initTracing(ctx context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if tp := a.configuration.TracerProvider; tp != nil {
+		a.tracerProvider = tp
+		a.tracer = tp.Tracer(tracerName, trace.WithInstrumentationVersion(grffrVersion))
+		return nil
+	}
+
+	if a.environment.OTel.SDKDisabled {
+		slog.DebugContext(ctx, "Tracing disabled.")
+		a.tracerProvider = noop.NewTracerProvider()
+		a.tracer = a.tracerProvider.Tracer(tracerName)
+		return nil
+	}
+
+	res, err := a.newResource(ctx)
+	if err != nil {
+		return fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+	}
+	exporter, err := a.newTraceExporter(ctx)
+	if err != nil {
+		return fmt.Errorf("creating trace exporter: %w", err)
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	a.tracerProvider = tp
+	a.tracer = tp.Tracer(tracerName, trace.WithInstrumentationVersion(grffrVersion))
+	a.tracerShutdown = tp.Shutdown
+
+	return nil
+}
+
+// newResource describes the application for telemetry.
+//
+// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the
+// application's name and version.
+func (a *App) newResource(ctx context.Context) (*resource.Resource, error) {
+	return resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(a.buildInfo.Name),
+			semconv.ServiceVersion(a.buildInfo.Version),
+		),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+}
+
+// newTraceExporter creates the exporter selected by OTEL_TRACES_EXPORTER.
+//
+// The exporter is nil if traces should not be exported.
+func (a *App) newTraceExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	env := a.environment.OTel
+	name := strings.ToLower(env.TracesExporter)
+	if name == "" {
+		name = "none"
+		if env.OTLPEndpoint != "" || env.OTLPTracesEndpoint != "" {
+			name = "otlp"
+		}
+	}
+
+	switch name {
+	case "none":
+		slog.DebugContext(ctx, "Traces are not exported.")
+		return nil, nil
+	case "otlp":
+		slog.DebugContext(ctx, "Exporting traces with OTLP/HTTP.")
+		return otlptracehttp.New(ctx)
+	case "console", "stdout":
+		slog.DebugContext(ctx, "Exporting traces to stdout.")
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		path := env.TracesFile
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		slog.DebugContext(ctx, "Exporting traces to file.", slog.String("path", path))
+		a.tracesFile = f
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q", name)
+	}
+}
+
+// shutdownTracing flushes and shuts down the tracer provider,
+// if configured by the application, then closes the traces file.
+func (a *App) shutdownTracing(ctx context.Context) error {
+	if a.tracerShutdown == nil {
+		return nil
+	}
+
+	err := a.tracerShutdown(ctx)
+	if a.tracesFile != nil {
+		err = errors.Join(err, a.tracesFile.Close())
+	}
+
+	return err
+}
+
+// traceRequests is a middleware starting a server span for every request.
+//
+// Trace context and baggage are extracted from the request headers, and
+// the span is put into the request context. The span is named after the
+// matched route pattern rather than the raw path.
+func (a *App) traceRequests(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !a.traced(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := a.tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				semconv.ServerAddress(r.Host),
+				semconv.ClientAddress(r.RemoteAddr),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+		)
+		defer span.End()
+		if r.ContentLength > 0 {
+			span.SetAttributes(semconv.HTTPRequestBodySize(int(r.ContentLength)))
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(status),
+			semconv.HTTPResponseBodySize(ww.BytesWritten()),
+		)
+		if route := routePattern(r); route != "" {
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+	return http.HandlerFunc(fn)
+}
+
+// traced reports whether the request should be traced.
+func (a *App) traced(r *http.Request) bool {
+	if slices.Contains(a.configuration.TracingExcludedPaths, r.URL.Path) {
+		return false
+	}
+	if !a.configuration.TraceHealth && a.isHealthPath(r.URL.Path) {
+		return false
+	}
+
+	return true
+}
+
+// routePattern returns the route pattern matched by chi, if any.
+//
+// The pattern is only complete once the request has been routed.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+
+	return rctx.RoutePattern()
+}
@@ -6,76 +6,68 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
-	"os"
-	"strconv"
-	"time"
+	"slices"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// Router returns the router of the application's HTTP server.
+//
+// Application routes should be registered before calling Run.
+func (a *App) Router() chi.Router {
+	return a.router
+}
+
+// Mount attaches handler on the application's HTTP server at pattern.
+//
+// See [chi.Router] Mount for pattern matching.
+func (a *App) Mount(pattern string, handler http.Handler) {
+	a.router.Mount(pattern, handler)
+}
+
 func (a *App) initWebServer() error {
-	port, err := strconv.Atoi(os.Getenv("HTTP_PORT"))
+	addr := a.configuration.HTTPAddr
+	err := addrAvailable(addr)
 	if err != nil {
-		slog.Debug("Using default port :80")
-		port = 80
+		return fmt.Errorf("address %s unavailable: %w", addr, err)
 	}
 
-	err = portAvailable(port)
-	if err != nil {
-		return fmt.Errorf("port %d unavailable: %w", port, err)
+	mux := a.router
+	for fn := range slices.Values(a.configuration.Routes) {
+		fn(mux)
 	}
 
-	mux := chi.NewMux()
-
-	// Health checks, propes and status
-	mux.Route("/.well-known/health", func(r chi.Router) {
-
-		// Is application started up?
-		//
-		// Don't do other health checks until the application has
-		// declared itself as started.
-		if a.configuration.StartupHandler != nil {
-			r.Handle("GET /startup", a.configuration.StartupHandler)
+	// Speak one error format for unmatched routes
+	for router := range slices.Values([]*chi.Mux{mux, a.adminRouter}) {
+		if h := a.configuration.NotFoundHandler; h != nil {
+			router.NotFound(h.ServeHTTP)
 		}
-
-		// Is application alive?
-		//
-		// If not alive then the application/process/container should be
-		// terminated and not be sent any traffic.
-		if a.configuration.LivenessHandler != nil {
-			r.Handle("GET /alive", a.configuration.LivenessHandler)
-		}
-
-		// Is application ready to serve?
-		//
-		// Determines if the application is currently ready to serve traffic.
-		// If not then wait some time and ask again if ready.
-		// Not being ready is a temporary state unlike not being alive.
-		if a.configuration.ReadinessHandler != nil {
-			r.Handle("GET /ready", a.configuration.ReadinessHandler)
+		if h := a.configuration.MethodNotAllowedHandler; h != nil {
+			router.MethodNotAllowed(allowMethods(router, h).ServeHTTP)
 		}
+	}
 
-		// What's the health status of the application?
-		//
-		// This is a more detailed report on the applications health.
-		if a.configuration.StatusHandler != nil {
-			r.Handle("GET /status", a.configuration.StatusHandler)
-		}
-	})
+	// Health checks are served by the admin server when enabled,
+	// keeping them off the public HTTP server.
+	if a.adminEnabled() {
+		a.routeHealth(a.adminRouter)
+	} else {
+		a.routeHealth(mux)
+	}
 
 	// Configure web server
 	inflightCtx, inflightCancel := context.WithCancel(context.Background())
+	timeouts := a.configuration.HTTPTimeouts
 	a.httpServer = http.Server{
-		Addr:    fmt.Sprintf(":%v", port),
+		Addr:    addr,
 		Handler: mux,
 
-		// TODO: Timeouts should probably not be hard-coded in a framework like this
-		// as use cases are unknown and some services might need short timeouts, and other might
-		//  need longer timeouts.
-		ReadTimeout:       2 * time.Minute,
-		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      time.Minute,
-		IdleTimeout:       time.Minute,
+		ReadTimeout:       timeouts.Read,
+		ReadHeaderTimeout: timeouts.ReadHeader,
+		WriteTimeout:      timeouts.Write,
+		IdleTimeout:       timeouts.Idle,
+		MaxHeaderBytes:    a.configuration.HTTPMaxHeaderBytes,
 		BaseContext: func(net.Listener) context.Context {
 			return inflightCtx
 		},
@@ -90,3 +82,103 @@ func (a *App) initWebServer() error {
 
 	return nil
 }
+
+// methods are the request methods reported in the Allow header.
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// allowMethods sets the Allow header to the methods routed by router for
+// the request path, as required for 405 responses, before calling next.
+func allowMethods(router chi.Routes, next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.RawPath
+		if path == "" {
+			path = r.URL.Path
+		}
+
+		var allowed []string
+		for method := range slices.Values(methods) {
+			if router.Match(chi.NewRouteContext(), method, path) {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+		}
+
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// isHealthPath reports whether path is served by a health or version end-point.
+func (a *App) isHealthPath(path string) bool {
+	cfg := a.configuration
+	if slices.Contains(cfg.VersionPaths, path) {
+		return true
+	}
+	path, ok := strings.CutPrefix(path, cfg.HealthPrefix)
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(cfg.StartupPaths, path) ||
+		slices.Contains(cfg.LivenessPaths, path) ||
+		slices.Contains(cfg.ReadinessPaths, path) ||
+		slices.Contains(cfg.StatusPaths, path)
+}
+
+// routeHealth registers health checks, probes, status and version on mux.
+func (a *App) routeHealth(mux chi.Router) {
+	cfg := a.configuration
+	handle := func(paths []string, h http.Handler) {
+		if h == nil {
+			return
+		}
+		for path := range slices.Values(paths) {
+			mux.Handle("GET "+cfg.HealthPrefix+path, h)
+		}
+	}
+
+	// Is application started up?
+	//
+	// Don't do other health checks until the application has
+	// declared itself as started.
+	handle(cfg.StartupPaths, cfg.StartupHandler)
+
+	// Is application alive?
+	//
+	// If not alive then the application/process/container should be
+	// terminated and not be sent any traffic.
+	handle(cfg.LivenessPaths, cfg.LivenessHandler)
+
+	// Is application ready to serve?
+	//
+	// Determines if the application is currently ready to serve traffic.
+	// If not then wait some time and ask again if ready.
+	// Not being ready is a temporary state unlike not being alive.
+	if cfg.ReadinessHandler != nil {
+		handle(cfg.ReadinessPaths, a.readinessProbe(cfg.ReadinessHandler))
+	}
+
+	// What's the health status of the application?
+	//
+	// This is a more detailed report on the applications health.
+	handle(cfg.StatusPaths, cfg.StatusHandler)
+
+	// Which version of the application is running?
+	if cfg.VersionHandler != nil {
+		for path := range slices.Values(cfg.VersionPaths) {
+			mux.Handle("GET "+path, cfg.VersionHandler)
+		}
+	}
+}